@@ -1,7 +1,8 @@
 package config
 
 import (
-	"strings"
+	"sort"
+	"strconv"
 )
 
 // cloneValue realiza una copia profunda de cualquier valor.
@@ -25,24 +26,51 @@ func cloneValue(v interface{}) interface{} {
 		return val
 	}
 }
+
+// getRawValue devuelve la referencia del valor ya interpolado. Si la interpolación falla
+// se devuelve el valor tal como está almacenado; GetXxxE reporta el error.
 func (c *Config) getRawValue(key string) (interface{}, bool) {
+	v, ok := c.lookupKey(key)
+	if !ok {
+		return nil, false
+	}
+	if r, err := c.resolve(v); err == nil {
+		return r, true
+	}
+	return v, true
+}
+
+// lookupKey devuelve la referencia del valor almacenado, sin interpolar.
+func (c *Config) lookupKey(key string) (interface{}, bool) {
 	if key == "" {
 		return nil, false
 	}
 
-	keys := strings.Split(key, c.opts.Separator)
-	var current interface{} = c.data
+	return lookup(c.data, splitKey(key, c.opts.Separator))
+}
+
+// lookup recorre el mapa siguiendo los segmentos de la clave.
+// Los segmentos numéricos acceden a los elementos de los slices.
+func lookup(data map[string]interface{}, keys []string) (interface{}, bool) {
+	var current interface{} = data
 
 	for _, k := range keys {
-		m, ok := current.(map[string]interface{})
-		if !ok {
-			return nil, false
-		}
-		val, exists := m[k]
-		if !exists {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			val, exists := node[k]
+			if !exists {
+				return nil, false
+			}
+			current = val
+		case []interface{}:
+			idx, err := strconv.Atoi(k)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+			current = node[idx]
+		default:
 			return nil, false
 		}
-		current = val
 	}
 
 	return current, true
@@ -77,33 +105,50 @@ func (c *Config) getRawSlice(key string) ([]interface{}, bool) {
 	return s, ok
 }
 
-func (c *Config) set(key string, value interface{}) error {
-	c.mu.Lock()         // Bloqueo total: nadie puede leer ni escribir
-	defer c.mu.Unlock() // Se libera automáticamente al terminar la función
+// set establece el valor dentro de la fuente editable de la capa indicada.
+func (c *Config) set(layer Layer, key string, value interface{}) error {
+	return c.setMany(layer, map[string]interface{}{key: value})
+}
 
-	if key == "" {
-		return ErrKeyEmpty
+// setMany establece varios valores en una sola actualización. Las claves se aplican en orden
+// alfabético, de modo que una clave padre se asigna antes que sus hijas. Las claves con índices
+// sobre slices de otras fuentes se guardan como patches del elemento.
+func (c *Config) setMany(layer Layer, values map[string]interface{}) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if k == "" {
+			return ErrKeyEmpty
+		}
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 
-	keys := strings.Split(key, c.opts.Separator)
-	cm := c.data
-
-	for i := 0; i < len(keys)-1; i++ {
-		k := keys[i]
-		next, exists := cm[k]
-
-		if exists {
-			if m, ok := next.(map[string]interface{}); ok {
-				cm = m
-				continue
+	return c.update(func(sources []*source) ([]*source, error) {
+		s := layerSource(sources, layer)
+		for _, k := range keys {
+			if err := s.write(c.data, splitKey(k, c.opts.Separator), cloneValue(values[k])); err != nil {
+				return nil, err
 			}
 		}
+		return withSource(sources, s), nil
+	})
+}
 
-		nm := make(map[string]interface{})
-		cm[k] = nm
-		cm = nm
+// walkLeaves recorre en orden alfabético los valores que no son mapas, entregando la ruta
+// completa de segmentos de cada uno.
+func walkLeaves(m map[string]interface{}, prefix []string, fn func(keys []string, v interface{})) {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
 	}
+	sort.Strings(names)
 
-	cm[keys[len(keys)-1]] = cloneValue(value)
-	return nil
+	for _, k := range names {
+		keys := append(append(make([]string, 0, len(prefix)+1), prefix...), k)
+		if sub, ok := m[k].(map[string]interface{}); ok {
+			walkLeaves(sub, keys, fn)
+			continue
+		}
+		fn(keys, m[k])
+	}
 }
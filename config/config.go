@@ -1,5 +1,10 @@
 package config
 
+import (
+	"net/url"
+	"time"
+)
+
 // IConfig
 // ------------------------------------------------------------------------------------------------
 type IConfig interface {
@@ -21,9 +26,95 @@ type IConfig interface {
 	GetSliceFloat(keys string) []float64
 	GetSliceBool(keys string) []bool
 
+	GetE(keys string) (interface{}, error)
+	GetStringE(keys string) (string, error)
+	GetIntE(keys string) (int, error)
+	GetFloatE(keys string) (float64, error)
+	GetBoolE(keys string) (bool, error)
+
+	GetMapE(keys string) (map[string]interface{}, error)
+	GetMapStringE(keys string) (map[string]string, error)
+	GetMapIntE(keys string) (map[string]int, error)
+	GetMapFloatE(keys string) (map[string]float64, error)
+	GetMapBoolE(keys string) (map[string]bool, error)
+
+	GetSliceE(keys string) ([]interface{}, error)
+	GetSliceStringE(keys string) ([]string, error)
+	GetSliceIntE(keys string) ([]int, error)
+	GetSliceFloatE(keys string) ([]float64, error)
+	GetSliceBoolE(keys string) ([]bool, error)
+
+	GetInt64(keys string) int64
+	GetInt32(keys string) int32
+	GetUint64(keys string) uint64
+	GetUint(keys string) uint
+
+	GetMapInt64(keys string) map[string]int64
+	GetMapInt32(keys string) map[string]int32
+	GetMapUint64(keys string) map[string]uint64
+	GetMapUint(keys string) map[string]uint
+
+	GetSliceInt64(keys string) []int64
+	GetSliceInt32(keys string) []int32
+	GetSliceUint64(keys string) []uint64
+	GetSliceUint(keys string) []uint
+
+	GetInt64E(keys string) (int64, error)
+	GetInt32E(keys string) (int32, error)
+	GetUint64E(keys string) (uint64, error)
+	GetUintE(keys string) (uint, error)
+
+	GetMapInt64E(keys string) (map[string]int64, error)
+	GetMapInt32E(keys string) (map[string]int32, error)
+	GetMapUint64E(keys string) (map[string]uint64, error)
+	GetMapUintE(keys string) (map[string]uint, error)
+
+	GetSliceInt64E(keys string) ([]int64, error)
+	GetSliceInt32E(keys string) ([]int32, error)
+	GetSliceUint64E(keys string) ([]uint64, error)
+	GetSliceUintE(keys string) ([]uint, error)
+
+	GetDuration(keys string) time.Duration
+	GetTime(keys string) time.Time
+	GetBytesSize(keys string) int64
+	GetURL(keys string) *url.URL
+
+	GetMapDuration(keys string) map[string]time.Duration
+	GetMapTime(keys string) map[string]time.Time
+	GetMapBytesSize(keys string) map[string]int64
+	GetMapURL(keys string) map[string]*url.URL
+
+	GetSliceDuration(keys string) []time.Duration
+	GetSliceTime(keys string) []time.Time
+	GetSliceBytesSize(keys string) []int64
+	GetSliceURL(keys string) []*url.URL
+
+	GetDurationE(keys string) (time.Duration, error)
+	GetTimeE(keys string) (time.Time, error)
+	GetBytesSizeE(keys string) (int64, error)
+	GetURLE(keys string) (*url.URL, error)
+
+	GetMapDurationE(keys string) (map[string]time.Duration, error)
+	GetMapTimeE(keys string) (map[string]time.Time, error)
+	GetMapBytesSizeE(keys string) (map[string]int64, error)
+	GetMapURLE(keys string) (map[string]*url.URL, error)
+
+	GetSliceDurationE(keys string) ([]time.Duration, error)
+	GetSliceTimeE(keys string) ([]time.Time, error)
+	GetSliceBytesSizeE(keys string) ([]int64, error)
+	GetSliceURLE(keys string) ([]*url.URL, error)
+
+	Unmarshal(keys string, out any) error
+
 	HasKey(keys string, valueType ValueType) bool
 	GetKeys(keys string) []string
+	Query(pattern string) []interface{}
+	GetPath(path []string) interface{}
+	Sub(prefix string) IConfig
 	Set(key string, value interface{}) error
+	Delete(key string) error
+	Unset(key string) error
+	Replace(key string, value map[string]interface{}) error
 }
 
 // ------------------------------------------------------------------------------------------------
@@ -33,15 +124,19 @@ type IConfig interface {
 type ValueType string
 
 const (
-	Map   ValueType = "Map"
-	Slice ValueType = "Slice"
+	Map    ValueType = "Map"
+	Slice  ValueType = "Slice"
+	String ValueType = "String"
+	Int    ValueType = "Int"
+	Float  ValueType = "Float"
+	Bool   ValueType = "Bool"
 )
 
 // Get devuelve el valor asociado a la clave especificada como interface{}.
 // Si la clave no existe, retorna nil.
 func (c *Config) Get(keys string) interface{} {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	v, _ := c.getValue(keys)
 	return v
 }
@@ -49,10 +144,10 @@ func (c *Config) Get(keys string) interface{} {
 // GetString devuelve el valor de la clave como string.
 // Si no existe o no es convertible, retorna una cadena vacía.
 func (c *Config) GetString(keys string) string {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if v, ok := c.getRawValue(keys); ok {
-		return toString(v)
+		return c.opts.StringPolicy.toString(v)
 	}
 	return ""
 }
@@ -60,8 +155,8 @@ func (c *Config) GetString(keys string) string {
 // GetInt devuelve el valor de la clave como int.
 // Si no existe o no es convertible, retorna 0.
 func (c *Config) GetInt(keys string) int {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if v, ok := c.getRawValue(keys); ok {
 		return toInt(v)
 	}
@@ -71,8 +166,8 @@ func (c *Config) GetInt(keys string) int {
 // GetFloat devuelve el valor de la clave como float64.
 // Si no existe o no es convertible, retorna 0.0.
 func (c *Config) GetFloat(keys string) float64 {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if v, ok := c.getRawValue(keys); ok {
 		return toFloat64(v)
 	}
@@ -82,8 +177,8 @@ func (c *Config) GetFloat(keys string) float64 {
 // GetBool devuelve el valor de la clave como bool.
 // Si no existe o no es convertible, retorna false.
 func (c *Config) GetBool(keys string) bool {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if v, ok := c.getRawValue(keys); ok {
 		return toBool(v)
 	}
@@ -93,8 +188,8 @@ func (c *Config) GetBool(keys string) bool {
 // GetMap devuelve el valor de la clave como un map[string]interface{} clonado de forma segura.
 // Si la clave no existe o no es un mapa, retorna un mapa vacío.
 func (c *Config) GetMap(keys string) map[string]interface{} {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if m, ok := c.getRawMap(keys); ok {
 		// Al ser un mapa genérico mutable, lo clonamos antes de entregarlo
 		return cloneValue(m).(map[string]interface{})
@@ -105,10 +200,10 @@ func (c *Config) GetMap(keys string) map[string]interface{} {
 // GetMapString convierte y retorna un map[string]string a partir de la referencia original.
 // Si la clave no existe o no es convertible, retorna un mapa vacío.
 func (c *Config) GetMapString(keys string) map[string]string {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if m, ok := c.getRawMap(keys); ok {
-		if r, ok := convertToStringMap(m); ok {
+		if r, ok := convertToStringMap(m, c.opts.StringPolicy); ok {
 			return r
 		}
 	}
@@ -118,8 +213,8 @@ func (c *Config) GetMapString(keys string) map[string]string {
 // GetMapInt convierte y retorna un map[string]int a partir de la referencia original.
 // Si la clave no existe o no es convertible, retorna un mapa vacío.
 func (c *Config) GetMapInt(keys string) map[string]int {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if m, ok := c.getRawMap(keys); ok {
 		if r, ok := convertToIntMap(m); ok {
 			return r
@@ -131,8 +226,8 @@ func (c *Config) GetMapInt(keys string) map[string]int {
 // GetMapFloat convierte y retorna un map[string]float64 a partir de la referencia original.
 // Si la clave no existe o no es convertible, retorna un mapa vacío.
 func (c *Config) GetMapFloat(keys string) map[string]float64 {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if m, ok := c.getRawMap(keys); ok {
 		if r, ok := convertToFloatMap(m); ok {
 			return r
@@ -144,8 +239,8 @@ func (c *Config) GetMapFloat(keys string) map[string]float64 {
 // GetMapBool convierte y retorna un map[string]bool a partir de la referencia original.
 // Si la clave no existe o no es convertible, retorna un mapa vacío.
 func (c *Config) GetMapBool(keys string) map[string]bool {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if m, ok := c.getRawMap(keys); ok {
 		if r, ok := convertToBoolMap(m); ok {
 			return r
@@ -157,8 +252,8 @@ func (c *Config) GetMapBool(keys string) map[string]bool {
 // GetSlice devuelve el valor de la clave como un []interface{} clonado de forma segura.
 // Si la clave no existe o no es un slice, retorna un slice vacío.
 func (c *Config) GetSlice(keys string) []interface{} {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if s, ok := c.getRawSlice(keys); ok {
 		// Al ser un slice genérico mutable, lo clonamos antes de entregarlo
 		return cloneValue(s).([]interface{})
@@ -169,10 +264,10 @@ func (c *Config) GetSlice(keys string) []interface{} {
 // GetSliceString convierte y retorna un []string a partir de la referencia original.
 // Si la clave no existe o no es convertible, retorna un slice vacío.
 func (c *Config) GetSliceString(keys string) []string {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if s, ok := c.getRawSlice(keys); ok {
-		if r, ok := convertToStringSlice(s); ok {
+		if r, ok := convertToStringSlice(s, c.opts.StringPolicy); ok {
 			return r
 		}
 	}
@@ -182,8 +277,8 @@ func (c *Config) GetSliceString(keys string) []string {
 // GetSliceInt convierte y retorna un []int a partir de la referencia original.
 // Si la clave no existe o no es convertible, retorna un slice vacío.
 func (c *Config) GetSliceInt(keys string) []int {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if s, ok := c.getRawSlice(keys); ok {
 		if r, ok := convertToIntSlice(s); ok {
 			return r
@@ -195,8 +290,8 @@ func (c *Config) GetSliceInt(keys string) []int {
 // GetSliceFloat convierte y retorna un []float64.
 // Si la clave no existe o no es convertible, retorna un slice vacío.
 func (c *Config) GetSliceFloat(keys string) []float64 {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if s, ok := c.getRawSlice(keys); ok {
 		if r, ok := convertToFloatSlice(s); ok {
 			return r
@@ -208,8 +303,8 @@ func (c *Config) GetSliceFloat(keys string) []float64 {
 // GetSliceBool convierte y retorna un []bool.
 // Si la clave no existe o no es convertible, retorna un slice vacío.
 func (c *Config) GetSliceBool(keys string) []bool {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if s, ok := c.getRawSlice(keys); ok {
 		if r, ok := convertToBoolSlice(s); ok {
 			return r
@@ -219,10 +314,10 @@ func (c *Config) GetSliceBool(keys string) []bool {
 }
 
 // HasKey indica si una clave existe en la configuración.
-// También permite validar si es del tipo esperado (mapa o slice).
+// También permite validar si es del tipo esperado (mapa, slice o convertible a String, Int, Float o Bool).
 func (c *Config) HasKey(key string, valueType ValueType) bool {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	if valueType == "" {
 		_, ok := c.getRawValue(key)
 		return ok
@@ -235,6 +330,9 @@ func (c *Config) HasKey(key string, valueType ValueType) bool {
 	case Slice:
 		_, ok := c.getRawSlice(key)
 		return ok
+	case String, Int, Float, Bool:
+		v, ok := c.getRawValue(key)
+		return ok && matchesType(v, valueType)
 	default:
 		return false
 	}
@@ -243,8 +341,8 @@ func (c *Config) HasKey(key string, valueType ValueType) bool {
 // GetKeys retorna todas las claves del mapa asociado a la clave dada.
 // Si la clave no existe o no es un mapa, retorna un slice vacío.
 func (c *Config) GetKeys(keys string) []string {
-	c.mu.RLock()         // Bloqueo de lectura
-	defer c.mu.RUnlock() // Liberar al salir
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
 	m, ok := c.getRawMap(keys)
 	if !ok {
 		return []string{}
@@ -257,7 +355,111 @@ func (c *Config) GetKeys(keys string) []string {
 	return res
 }
 
+// GetPath devuelve una copia del valor en la ruta de segmentos indicada, sin interpretar
+// separadores, comillas ni corchetes. Si la ruta no existe, retorna nil.
+func (c *Config) GetPath(path []string) interface{} {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	if len(path) == 0 {
+		return nil
+	}
+
+	v, ok := lookup(c.data, path)
+	if !ok {
+		return nil
+	}
+	if r, err := c.resolve(v); err == nil {
+		v = r
+	}
+	return cloneValue(v)
+}
+
+// Query devuelve copias de todos los valores que coinciden con el patrón, en orden estable.
+// El segmento "*" coincide con cada clave de un mapa o cada elemento de un slice:
+// "servers.*.host" retorna el host de cada servidor. Si no hay coincidencias, retorna un slice vacío.
+func (c *Config) Query(pattern string) []interface{} {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	res := []interface{}{}
+	if pattern == "" {
+		return res
+	}
+
+	for _, m := range expand(c.data, splitKey(pattern, c.opts.Separator), nil) {
+		v, err := c.resolve(m.value)
+		if err != nil {
+			v = m.value
+		}
+		res = append(res, cloneValue(v))
+	}
+	return res
+}
+
 // Set establece o actualiza un valor dentro de la configuración utilizando una clave jerárquica.
+// Los valores establecidos en ejecución tienen la mayor precedencia.
 func (c *Config) Set(key string, value interface{}) error {
-	return c.set(key, value)
+	return c.set(LayerRuntime, key, value)
+}
+
+// Delete elimina la clave, sea un valor o una sección completa, de la vista combinada. La
+// eliminación se registra en la capa de ejecución sin modificar las capas inferiores, por lo que
+// se mantiene tras Reload y Watch y Unset la deshace. Si la clave apunta a un elemento de un
+// slice, el índice se refiere al slice combinado: el elemento se quita aunque varias fuentes lo
+// aporten, como con MergeAppendUnique o MergeByKey, y los siguientes se desplazan.
+// Eliminar una clave inexistente no es un error.
+func (c *Config) Delete(key string) error {
+	if key == "" {
+		return ErrKeyEmpty
+	}
+	segs := splitKey(key, c.opts.Separator)
+
+	return c.update(func(sources []*source) ([]*source, error) {
+		if _, ok := lookup(c.data, segs); !ok {
+			return sources, nil
+		}
+		s := layerSource(sources, LayerRuntime)
+		s.drop(c.data, segs)
+		return withSource(sources, s), nil
+	})
+}
+
+// Unset elimina únicamente el valor establecido con Set, Delete o Replace, de modo que la clave
+// vuelve a tomar el valor de las capas inferiores, si existe.
+// Las escrituras por índice se quitan completas: tras Set("servers.1.host", "c"),
+// Unset("servers.1.host") devuelve el elemento al valor de las capas inferiores.
+func (c *Config) Unset(key string) error {
+	if key == "" {
+		return ErrKeyEmpty
+	}
+	segs := splitKey(key, c.opts.Separator)
+
+	return c.update(func(sources []*source) ([]*source, error) {
+		s := layerSource(sources, LayerRuntime)
+		s.unset(c.data, segs)
+		return withSource(sources, s), nil
+	})
+}
+
+// Replace reemplaza la sección completa indicada por la clave en lugar de combinarla con la
+// existente: las claves que no estén en el nuevo mapa dejan de existir. El valor se establece
+// en la capa de ejecución, igual que con Set, sin modificar las capas inferiores: el reemplazo se
+// mantiene tras Reload y Unset devuelve la sección a sus valores anteriores.
+func (c *Config) Replace(key string, value map[string]interface{}) error {
+	if key == "" {
+		return ErrKeyEmpty
+	}
+	segs := splitKey(key, c.opts.Separator)
+
+	return c.update(func(sources []*source) ([]*source, error) {
+		s := layerSource(sources, LayerRuntime)
+		// Un patch sustituye el valor completo, por lo que solo fuera de los slices se necesita
+		// descartar la sección de las capas inferiores
+		if slicePrefix(c.data, segs) == 0 {
+			s.clear(segs)
+		}
+		if err := s.write(c.data, segs, cloneValue(value)); err != nil {
+			return nil, err
+		}
+		return withSource(sources, s), nil
+	})
 }
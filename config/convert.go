@@ -1,17 +1,22 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // convertToStringMap convierte un map[string]interface{} a map[string]string,
-// aplicando la conversión a string para cada valor.
-func convertToStringMap(input map[string]interface{}) (map[string]string, bool) {
+// aplicando la conversión a string de la política para cada valor.
+func convertToStringMap(input map[string]interface{}, policy StringPolicy) (map[string]string, bool) {
 	data := make(map[string]string)
 	for k, v := range input {
-		data[k] = toString(v)
+		data[k] = policy.toString(v)
 	}
 	return data, true
 }
@@ -47,11 +52,11 @@ func convertToBoolMap(input map[string]interface{}) (map[string]bool, bool) {
 }
 
 // convertToStringSlice convierte un slice []interface{} a []string,
-// aplicando la conversión a string para cada elemento.
-func convertToStringSlice(input []interface{}) ([]string, bool) {
+// aplicando la conversión a string de la política para cada elemento.
+func convertToStringSlice(input []interface{}, policy StringPolicy) ([]string, bool) {
 	result := make([]string, 0, len(input))
 	for _, v := range input {
-		result = append(result, toString(v))
+		result = append(result, policy.toString(v))
 	}
 	return result, true
 }
@@ -87,7 +92,8 @@ func convertToBoolSlice(input []interface{}) ([]bool, bool) {
 }
 
 // toString convierte cualquier valor básico a string,
-// manejando tipos numéricos, booleanos y cadenas.
+// manejando tipos numéricos, booleanos, cadenas y fechas. Los flotantes usan la
+// representación más corta que conserva su valor y las fechas el formato RFC 3339.
 // Para tipos no reconocidos devuelve cadena vacía.
 func toString(value interface{}) string {
 	switch v := value.(type) {
@@ -97,50 +103,25 @@ func toString(value interface{}) string {
 		return fmt.Sprintf("%d", v)
 	case uint, uint8, uint16, uint32, uint64:
 		return fmt.Sprintf("%d", v)
-	case float32, float64:
-		return fmt.Sprintf("%f", v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
 	case bool:
 		return fmt.Sprintf("%t", v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
 	default:
 		return ""
 	}
 }
 
 // toInt convierte un valor básico a int, realizando conversiones
-// seguras desde tipos numéricos y cadenas. Retorna 0 si no es convertible.
+// seguras desde tipos numéricos y cadenas. Retorna 0 si no es convertible,
+// si tiene parte decimal o si excede el rango de int.
 func toInt(value interface{}) int {
-	switch v := value.(type) {
-	case int:
-		return v
-	case int64:
-		return int(v)
-	case int32:
-		return int(v)
-	case uint:
-		return int(v)
-	case uint64:
-		return int(v)
-	case uint32:
-		return int(v)
-	case float64:
-		if v == float64(int(v)) {
-			return int(v)
-		}
-		return 0
-	case float32:
-		if v == float32(int(v)) {
-			return int(v)
-		}
-		return 0
-	case string:
-		i, err := strconv.Atoi(v)
-		if err == nil {
-			return i
-		}
-		return 0
-	default:
-		return 0
-	}
+	i, _ := toIntE(value)
+	return i
 }
 
 // toFloat64 convierte un valor básico a float64, incluyendo conversiones
@@ -193,3 +174,228 @@ func toBool(value interface{}) bool {
 		return false
 	}
 }
+
+// toInt64E convierte un valor básico a int64 sin perder información.
+// A diferencia de toInt, retorna un error si el valor no es convertible, tiene parte decimal
+// o excede el rango de int64.
+func toInt64E(value interface{}) (int64, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %d does not fit in int64", ErrOverflow, rv.Uint())
+		}
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != math.Trunc(f) {
+			return 0, fmt.Errorf("%w: %v has a fractional part", ErrTypeMismatch, f)
+		}
+		if f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %v does not fit in int64", ErrOverflow, f)
+		}
+		return int64(f), nil
+	case reflect.String:
+		i, err := strconv.ParseInt(strings.TrimSpace(rv.String()), 10, 64)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return 0, fmt.Errorf("%w: %q does not fit in int64", ErrOverflow, rv.String())
+			}
+			return 0, fmt.Errorf("%w: %q is not an integer", ErrTypeMismatch, rv.String())
+		}
+		return i, nil
+	default:
+		return 0, fmt.Errorf("%w: cannot convert %T to an integer", ErrTypeMismatch, value)
+	}
+}
+
+// toUint64E convierte un valor básico a uint64, rechazando negativos y valores con parte decimal.
+func toUint64E(value interface{}) (uint64, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() < 0 {
+			return 0, fmt.Errorf("%w: %d is negative", ErrOverflow, rv.Int())
+		}
+		return uint64(rv.Int()), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != math.Trunc(f) {
+			return 0, fmt.Errorf("%w: %v has a fractional part", ErrTypeMismatch, f)
+		}
+		if f < 0 || f >= math.MaxUint64 {
+			return 0, fmt.Errorf("%w: %v does not fit in uint64", ErrOverflow, f)
+		}
+		return uint64(f), nil
+	case reflect.String:
+		u, err := strconv.ParseUint(strings.TrimSpace(rv.String()), 10, 64)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return 0, fmt.Errorf("%w: %q does not fit in uint64", ErrOverflow, rv.String())
+			}
+			return 0, fmt.Errorf("%w: %q is not an unsigned integer", ErrTypeMismatch, rv.String())
+		}
+		return u, nil
+	default:
+		return 0, fmt.Errorf("%w: cannot convert %T to an unsigned integer", ErrTypeMismatch, value)
+	}
+}
+
+// toFloat64E convierte un valor básico a float64, retornando un error si no es convertible.
+func toFloat64E(value interface{}) (float64, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.String:
+		f, err := strconv.ParseFloat(strings.TrimSpace(rv.String()), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q is not a number", ErrTypeMismatch, rv.String())
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("%w: cannot convert %T to a number", ErrTypeMismatch, value)
+	}
+}
+
+// toBoolE convierte un valor a bool con las mismas reglas que toBool,
+// retornando un error en lugar de false cuando no es convertible.
+func toBoolE(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if strings.EqualFold(v, "true") {
+			return true, nil
+		} else if strings.EqualFold(v, "false") {
+			return false, nil
+		}
+		return false, fmt.Errorf("%w: %q is not a boolean", ErrTypeMismatch, v)
+	default:
+		return false, fmt.Errorf("%w: cannot convert %T to bool", ErrTypeMismatch, value)
+	}
+}
+
+// toStringE convierte un valor escalar a string con las mismas reglas que toString,
+// retornando un error para mapas, slices y tipos no reconocidos.
+func toStringE(value interface{}) (string, error) {
+	switch value.(type) {
+	case string, bool,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64, time.Time:
+		return toString(value), nil
+	default:
+		return "", fmt.Errorf("%w: cannot convert %T to string", ErrTypeMismatch, value)
+	}
+}
+
+// toDurationE convierte cadenas con el formato de time.ParseDuration ("30s", "1h30m")
+// y enteros, interpretados como nanosegundos igual que time.Duration.
+func toDurationE(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q is not a duration", ErrTypeMismatch, v)
+		}
+		return d, nil
+	default:
+		i, err := toInt64E(value)
+		if err != nil {
+			return 0, fmt.Errorf("%w: cannot convert %T to a duration", ErrTypeMismatch, value)
+		}
+		return time.Duration(i), nil
+	}
+}
+
+// toTimeE convierte cadenas usando los layouts indicados, en orden. Los valores que el
+// decodificador ya entregó como time.Time (YAML, TOML) se retornan sin cambios.
+func toTimeE(value interface{}, layouts []string) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		s := strings.TrimSpace(v)
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("%w: %q does not match any time layout", ErrTypeMismatch, v)
+	default:
+		return time.Time{}, fmt.Errorf("%w: cannot convert %T to time", ErrTypeMismatch, value)
+	}
+}
+
+// byteUnits contiene los multiplicadores de tamaño: los sufijos KB, MB, ... son decimales
+// (potencias de 1000) y KiB, MiB, ... binarios (potencias de 1024).
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// toBytesSizeE convierte tamaños como "512MB", "1.5 GiB" o "1024" a bytes. Los números sin
+// unidad se interpretan como bytes. Las unidades no distinguen mayúsculas.
+func toBytesSizeE(value interface{}) (int64, error) {
+	s, ok := value.(string)
+	if !ok {
+		return toInt64E(value)
+	}
+
+	s = strings.TrimSpace(s)
+	i := strings.LastIndexAny(s, "0123456789.") + 1
+	mult, ok := byteUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok || i == 0 {
+		return 0, fmt.Errorf("%w: %q is not a byte size", ErrTypeMismatch, value)
+	}
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %q is not a byte size", ErrTypeMismatch, value)
+	}
+
+	size := n * mult
+	if size != math.Trunc(size) {
+		return 0, fmt.Errorf("%w: %q is not a whole number of bytes", ErrTypeMismatch, value)
+	}
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("%w: %q does not fit in int64", ErrOverflow, value)
+	}
+	return int64(size), nil
+}
+
+// toURLE interpreta una cadena no vacía con url.Parse.
+func toURLE(value interface{}) (*url.URL, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot convert %T to a URL", ErrTypeMismatch, value)
+	}
+	if strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("%w: empty URL", ErrTypeMismatch)
+	}
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTypeMismatch, err)
+	}
+	return u, nil
+}
@@ -0,0 +1,283 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tagName = "config"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	urlType      = reflect.TypeOf(url.URL{})
+)
+
+// Unmarshal decodifica el subárbol de la clave en la estructura, mapa o slice apuntado por out.
+// Con la clave vacía se decodifica la configuración completa.
+//
+// Los campos se asocian por la etiqueta `config:"nombre"` o, en su ausencia, por el nombre del
+// campo sin distinguir mayúsculas; `config:"-"` omite el campo. Las claves ausentes conservan el
+// valor previo del campo. Los errores de conversión se reportan por campo, con su ruta completa.
+func (c *Config) Unmarshal(key string, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return ErrInvalidTarget
+	}
+
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+
+	var v interface{} = c.data
+	if key != "" {
+		var ok bool
+		if v, ok = c.lookupKey(key); !ok {
+			return &KeyError{Key: key, Err: ErrKeyNotFound}
+		}
+	}
+	r, err := c.resolve(v)
+	if err != nil {
+		return &KeyError{Key: key, Type: typeName(v), Err: err}
+	}
+
+	d := &decoder{sep: c.opts.Separator, layouts: c.opts.TimeLayouts, policy: c.opts.StringPolicy}
+	d.decode(key, r, rv.Elem())
+	if len(d.errs) > 0 {
+		return errors.Join(d.errs...)
+	}
+	return nil
+}
+
+// decoder acumula los errores de cada campo en lugar de detenerse en el primero.
+type decoder struct {
+	sep     string
+	layouts []string     // Layouts para los campos time.Time
+	policy  StringPolicy // Conversión de los campos string, igual que en GetString
+	errs    []error
+}
+
+func (d *decoder) fail(path string, err error) {
+	d.errs = append(d.errs, fmt.Errorf("%w: %s: %w", ErrUnmarshal, path, err))
+}
+
+func (d *decoder) join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + d.sep + key
+}
+
+func (d *decoder) decode(path string, v interface{}, rv reflect.Value) {
+	if v == nil {
+		return
+	}
+
+	if fn, ok := lookupDecoder(rv.Type()); ok {
+		r, err := fn(v)
+		if err != nil {
+			d.fail(path, err)
+			return
+		}
+		if val := reflect.ValueOf(r); val.IsValid() {
+			rv.Set(val)
+		} else {
+			rv.Set(reflect.Zero(rv.Type()))
+		}
+		return
+	}
+
+	switch rv.Type() {
+	case durationType:
+		dur, err := toDurationE(v)
+		if err != nil {
+			d.fail(path, err)
+			return
+		}
+		rv.SetInt(int64(dur))
+		return
+	case timeType:
+		t, err := toTimeE(v, d.layouts)
+		if err != nil {
+			d.fail(path, err)
+			return
+		}
+		rv.Set(reflect.ValueOf(t))
+		return
+	case urlType:
+		u, err := toURLE(v)
+		if err != nil {
+			d.fail(path, err)
+			return
+		}
+		rv.Set(reflect.ValueOf(*u))
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(rv.Type().Elem())
+		if !rv.IsNil() {
+			elem.Elem().Set(rv.Elem())
+		}
+		d.decode(path, v, elem.Elem())
+		rv.Set(elem)
+
+	case reflect.Interface:
+		val := reflect.ValueOf(cloneValue(v))
+		if !val.Type().AssignableTo(rv.Type()) {
+			d.fail(path, fmt.Errorf("%w: cannot assign %T to %s", ErrTypeMismatch, v, rv.Type()))
+			return
+		}
+		rv.Set(val)
+
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			d.fail(path, fmt.Errorf("%w: cannot decode %T into %s", ErrTypeMismatch, v, rv.Type()))
+			return
+		}
+		d.decodeStruct(path, m, rv)
+
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			d.fail(path, fmt.Errorf("%w: cannot decode %T into %s", ErrTypeMismatch, v, rv.Type()))
+			return
+		}
+		if rv.Type().Key().Kind() != reflect.String {
+			d.fail(path, fmt.Errorf("%w: map key type %s is not supported", ErrTypeMismatch, rv.Type().Key()))
+			return
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMapWithSize(rv.Type(), len(m)))
+		}
+		for k, val := range m {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			d.decode(d.join(path, k), val, elem)
+			rv.SetMapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()), elem)
+		}
+
+	case reflect.Slice:
+		s, ok := v.([]interface{})
+		if !ok {
+			d.fail(path, fmt.Errorf("%w: cannot decode %T into %s", ErrTypeMismatch, v, rv.Type()))
+			return
+		}
+		res := reflect.MakeSlice(rv.Type(), len(s), len(s))
+		for i, val := range s {
+			d.decode(d.join(path, strconv.Itoa(i)), val, res.Index(i))
+		}
+		rv.Set(res)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64E(v)
+		if err == nil && rv.OverflowInt(i) {
+			err = fmt.Errorf("%w: %d does not fit in %s", ErrOverflow, i, rv.Type())
+		}
+		if err != nil {
+			d.fail(path, err)
+			return
+		}
+		rv.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := toUint64E(v)
+		if err == nil && rv.OverflowUint(u) {
+			err = fmt.Errorf("%w: %d does not fit in %s", ErrOverflow, u, rv.Type())
+		}
+		if err != nil {
+			d.fail(path, err)
+			return
+		}
+		rv.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64E(v)
+		if err == nil && rv.OverflowFloat(f) {
+			err = fmt.Errorf("%w: %v does not fit in %s", ErrOverflow, f, rv.Type())
+		}
+		if err != nil {
+			d.fail(path, err)
+			return
+		}
+		rv.SetFloat(f)
+
+	case reflect.Bool:
+		b, err := toBoolE(v)
+		if err != nil {
+			d.fail(path, err)
+			return
+		}
+		rv.SetBool(b)
+
+	case reflect.String:
+		s, err := d.policy.toStringE(v)
+		if err != nil {
+			d.fail(path, err)
+			return
+		}
+		rv.SetString(s)
+
+	default:
+		d.fail(path, fmt.Errorf("%w: unsupported type %s", ErrTypeMismatch, rv.Type()))
+	}
+}
+
+// decodeStruct asigna cada campo exportado a partir de la clave que le corresponde en el mapa.
+// Los campos embebidos sin etiqueta se decodifican con el mismo mapa.
+func (d *decoder) decodeStruct(path string, m map[string]interface{}, rv reflect.Value) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, hasTag := field.Tag.Lookup(tagName)
+		name, _, _ = strings.Cut(name, ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && !hasTag && indirectType(field.Type).Kind() == reflect.Struct {
+			d.decode(path, m, rv.Field(i))
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		key, val, ok := lookupField(m, name)
+		if !ok {
+			continue
+		}
+		d.decode(d.join(path, key), val, rv.Field(i))
+	}
+}
+
+// lookupField busca la clave exacta y, si no existe, sin distinguir mayúsculas.
+func lookupField(m map[string]interface{}, name string) (string, interface{}, bool) {
+	if v, ok := m[name]; ok {
+		return name, v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return k, v, true
+		}
+	}
+	return "", nil, false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MissingKeysError reúne todas las claves requeridas que no se encontraron.
+type MissingKeysError struct {
+	Keys []string
+}
+
+func (e *MissingKeysError) Error() string {
+	return fmt.Sprintf("config: missing required keys: %s", strings.Join(e.Keys, ", "))
+}
+
+// Is permite comparar el error con ErrKeyNotFound mediante errors.Is.
+func (e *MissingKeysError) Is(target error) bool {
+	return target == ErrKeyNotFound
+}
+
+// SetDefault establece el valor por defecto de una clave. Los valores por defecto tienen la menor
+// precedencia, por lo que solo se usan si ningún archivo, variable, flag o Set define la clave.
+func (c *Config) SetDefault(key string, value interface{}) error {
+	return c.set(LayerDefaults, key, value)
+}
+
+// SetDefaults establece varios valores por defecto a la vez, indexados por clave jerárquica.
+func (c *Config) SetDefaults(values map[string]interface{}) error {
+	return c.setMany(LayerDefaults, values)
+}
+
+// Require verifica que todas las claves existan en la configuración combinada.
+// Retorna un *MissingKeysError con todas las claves faltantes, o nil si no falta ninguna.
+func (c *Config) Require(keys ...string) error {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+
+	var missing []string
+	for _, k := range keys {
+		if _, ok := c.getRawValue(k); !ok {
+			missing = append(missing, k)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &MissingKeysError{Keys: missing}
+	}
+	return nil
+}
+
+// MustHave es igual que Require, pero entra en pánico si falta alguna clave.
+// Está pensado para validar la configuración al iniciar la aplicación.
+func (c *Config) MustHave(keys ...string) {
+	if err := c.Require(keys...); err != nil {
+		panic(err)
+	}
+}
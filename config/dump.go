@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Export escribe la configuración combinada en el formato indicado, con las claves ordenadas
+// para obtener diferencias estables y los secretos ocultos. FormatEnv emite una línea
+// clave=valor por cada valor, legible de nuevo con LoadBytes; los slices se escriben en JSON.
+func (c *Config) Export(w io.Writer, format Format) error {
+	c.rlock()
+	data := c.redacted()
+	sep := c.opts.Separator
+	c.runlock()
+
+	switch format {
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(data); err != nil {
+			return fmt.Errorf("%w: %v", ErrExport, err)
+		}
+		return enc.Close()
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(data); err != nil {
+			return fmt.Errorf("%w: %v", ErrExport, err)
+		}
+		return nil
+	case FormatEnv:
+		var err error
+		walkLeaves(data, nil, func(keys []string, v interface{}) {
+			if err == nil {
+				_, err = fmt.Fprintf(w, "%s=%s\n", joinKey(keys, sep), flatValue(v))
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrExport, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// AllKeys retorna, ordenadas, las claves completas de todos los valores que no son mapas.
+func (c *Config) AllKeys() []string {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+
+	res := []string{}
+	walkLeaves(c.data, nil, func(keys []string, _ interface{}) {
+		res = append(res, joinKey(keys, c.opts.Separator))
+	})
+	return res
+}
+
+// flatValue representa un valor en una línea clave=valor, entre comillas si contiene
+// espacios o caracteres que el parser de .env interpretaría.
+func flatValue(v interface{}) string {
+	var s string
+	switch val := v.(type) {
+	case []interface{}, map[string]interface{}:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return ""
+		}
+		s = string(data)
+	case nil:
+		return ""
+	default:
+		s = toString(val)
+	}
+
+	if s == "" || strings.ContainsAny(s, " \t#\"'\\\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	envNestingSeparator = "__"
+)
+
+// LoadEnv registra en la capa de entorno las variables de entorno que inician con el prefijo.
+// El prefijo y el guion bajo que lo sigue se eliminan, el resto se pasa a minúsculas y cada "__"
+// se traduce al Separator de las opciones: APP_DATABASE__HOST=db -> database.host = "db".
+// Los valores se guardan como cadenas, igual que en los archivos .env, y GetInt, GetBool y el
+// resto de getters los convierten al leerlos, de modo que APP_VERSION=1.10 conserva sus ceros.
+func (c *Config) LoadEnv(prefix string) error {
+	return c.addSource(&source{layer: LayerEnv, name: prefix, data: envToMap(os.Environ(), prefix)})
+}
+
+// envToMap construye el árbol de configuración a partir de entradas CLAVE=VALOR.
+func envToMap(environ []string, prefix string) map[string]interface{} {
+	m := make(map[string]interface{})
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		name = strings.ToLower(strings.TrimPrefix(name, prefix))
+		keys := strings.Split(name, envNestingSeparator)
+		if !validKeys(keys) {
+			continue
+		}
+		setPath(m, keys, value)
+	}
+	return m
+}
+
+// validKeys indica si ninguno de los segmentos de la clave está vacío.
+func validKeys(keys []string) bool {
+	for _, k := range keys {
+		if k == "" {
+			return false
+		}
+	}
+	return len(keys) > 0
+}
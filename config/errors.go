@@ -7,4 +7,35 @@ var (
 	ErrReadFile      = errors.New("failed to read config file")
 	ErrMarshalStruct = errors.New("failed to marshal struct")
 	ErrParseYAML     = errors.New("failed to parse YAML")
+	ErrParseJSON     = errors.New("failed to parse JSON")
+	ErrParseTOML     = errors.New("failed to parse TOML")
+	ErrParseEnv      = errors.New("failed to parse env file")
+
+	ErrUnsupportedFormat = errors.New("unsupported config format")
+	ErrExport            = errors.New("failed to export config")
+	ErrFlagsNotParsed    = errors.New("flag set has not been parsed")
+	ErrInvalidFlag       = errors.New("invalid flag")
+	ErrNothingToWatch    = errors.New("no files loaded with LoadFile to watch")
+	ErrNotModified       = errors.New("config source not modified")
+	ErrProvider          = errors.New("config provider failed")
+
+	ErrKeyNotFound   = errors.New("key not found")
+	ErrTypeMismatch  = errors.New("type mismatch")
+	ErrOverflow      = errors.New("value out of range")
+	ErrInvalidTarget = errors.New("unmarshal target must be a non-nil pointer")
+	ErrUnmarshal     = errors.New("failed to unmarshal config")
+
+	ErrIndexOutOfRange = errors.New("index out of range")
+	ErrReadOnly        = errors.New("config view is read-only")
+	ErrMergeStrategy   = errors.New("invalid merge strategy")
+	ErrInclude         = errors.New("invalid include directive")
+	ErrIncludeCycle    = errors.New("include cycle")
+
+	ErrValidation  = errors.New("config validation failed")
+	ErrParseSchema = errors.New("failed to parse schema")
+
+	ErrInterpolation      = errors.New("failed to interpolate value")
+	ErrInterpolationCycle = errors.New("interpolation cycle detected")
+	ErrResolveSecret      = errors.New("failed to resolve secret")
+	ErrSecretKey          = errors.New("invalid secret key")
 )
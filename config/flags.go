@@ -0,0 +1,113 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LoadFlags registra en la capa de flags los flags que el usuario estableció explícitamente.
+// El nombre del flag es la clave jerárquica (por ejemplo, -database.port=5432); los flags que
+// conservan su valor por defecto no se combinan para no pisar valores de archivos o del entorno.
+func (c *Config) LoadFlags(fs *flag.FlagSet) error {
+	if !fs.Parsed() {
+		return ErrFlagsNotParsed
+	}
+
+	m := make(map[string]interface{})
+	fs.Visit(func(f *flag.Flag) {
+		keys := strings.Split(f.Name, c.opts.Separator)
+		if !validKeys(keys) {
+			return
+		}
+		if g, ok := f.Value.(flag.Getter); ok {
+			setPath(m, keys, g.Get())
+			return
+		}
+		setPath(m, keys, f.Value.String())
+	})
+
+	return c.addSource(&source{layer: LayerFlags, name: fs.Name(), data: m})
+}
+
+// LoadArgs registra en la capa de flags argumentos crudos con el formato --clave=valor,
+// --clave valor o --clave (equivalente a true), como los recibidos en os.Args[1:]. Un valor
+// numérico negativo se toma como valor del flag: --offset -1.
+// Los argumentos posicionales se ignoran y "--" detiene el análisis. Los valores se guardan como
+// cadenas y se convierten al leerlos.
+func (c *Config) LoadArgs(args []string) error {
+	m := make(map[string]interface{})
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		name, value, hasValue := strings.Cut(name, "=")
+		if !hasValue {
+			if i+1 < len(args) && isArgValue(args[i+1]) {
+				value = args[i+1]
+				i++
+			} else {
+				value = "true"
+			}
+		}
+
+		keys := strings.Split(name, c.opts.Separator)
+		if !validKeys(keys) {
+			return fmt.Errorf("%w: %q", ErrInvalidFlag, arg)
+		}
+		setPath(m, keys, value)
+	}
+
+	return c.addSource(&source{layer: LayerFlags, name: "args", data: m})
+}
+
+// BindFlags registra en el FlagSet un flag por cada clave escalar ya cargada, usando el valor actual
+// como valor por defecto, para que --help las liste. Los flags ya definidos se respetan.
+func (c *Config) BindFlags(fs *flag.FlagSet) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+
+	walkLeaves(c.data, nil, func(keys []string, v interface{}) {
+		name := strings.Join(keys, c.opts.Separator)
+		if fs.Lookup(name) != nil {
+			return
+		}
+
+		usage := fmt.Sprintf("config key %q", name)
+		switch val := v.(type) {
+		case bool:
+			fs.Bool(name, val, usage)
+		case int:
+			fs.Int(name, val, usage)
+		case float64:
+			fs.Float64(name, val, usage)
+		case string:
+			fs.String(name, val, usage)
+		case map[string]interface{}, []interface{}:
+			// Solo se exponen valores escalares
+		default:
+			fs.String(name, toString(val), usage)
+		}
+	})
+}
+
+// isArgValue indica si el argumento es el valor del flag anterior: no inicia con "-" o es un número.
+func isArgValue(arg string) bool {
+	if !strings.HasPrefix(arg, "-") {
+		return true
+	}
+	// Se exige un dígito tras el signo para no tomar flags como -inf o -nan por números
+	if len(arg) < 2 || !strings.ContainsRune("0123456789.", rune(arg[1])) {
+		return false
+	}
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
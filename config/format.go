@@ -0,0 +1,222 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Format
+// ------------------------------------------------------------------------------------------------
+
+type Format string
+
+const (
+	FormatYAML Format = "YAML"
+	FormatJSON Format = "JSON"
+	FormatTOML Format = "TOML"
+	FormatEnv  Format = "ENV"
+)
+
+// formatFromPath deduce el formato del archivo a partir de su extensión.
+// Los archivos sin extensión reconocida se tratan como YAML para conservar el comportamiento previo.
+func formatFromPath(path string) Format {
+	base := strings.ToLower(filepath.Base(path))
+	switch ext := filepath.Ext(base); {
+	case ext == ".json":
+		return FormatJSON
+	case ext == ".toml":
+		return FormatTOML
+	case ext == ".env" || base == ".env" || strings.HasPrefix(base, ".env."):
+		return FormatEnv
+	default:
+		return FormatYAML
+	}
+}
+
+// unmarshalToMap decodifica los bytes según el formato indicado y normaliza el resultado
+// al árbol map[string]interface{} que utilizan mergeSources y los accesores.
+func unmarshalToMap(data []byte, format Format, separator string) (map[string]interface{}, error) {
+	var m map[string]interface{}
+
+	switch format {
+	case FormatYAML, "":
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrParseYAML, err)
+		}
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrParseJSON, err)
+		}
+		// El contenido debe terminar tras el primer valor, igual que en YAML y TOML
+		if err := dec.Decode(new(interface{})); !errors.Is(err, io.EOF) {
+			if err == nil {
+				err = errors.New("unexpected content after top-level value")
+			}
+			return nil, fmt.Errorf("%w: %v", ErrParseJSON, err)
+		}
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrParseTOML, err)
+		}
+	case FormatEnv:
+		env, err := parseEnv(data)
+		if err != nil {
+			return nil, err
+		}
+		m = make(map[string]interface{}, len(env))
+		for k, v := range env {
+			setPath(m, splitKey(k, separator), v)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+
+	if m == nil {
+		return map[string]interface{}{}, nil
+	}
+	return normalizeValue(m).(map[string]interface{}), nil
+}
+
+// unmarshalDocuments decodifica todos los documentos de un archivo YAML separados por "---",
+// en orden y omitiendo los vacíos. Los demás formatos contienen un único documento.
+func unmarshalDocuments(data []byte, format Format, separator string) ([]map[string]interface{}, error) {
+	if format != FormatYAML && format != "" {
+		m, err := unmarshalToMap(data, format, separator)
+		if err != nil {
+			return nil, err
+		}
+		return []map[string]interface{}{m}, nil
+	}
+
+	var docs []map[string]interface{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var m map[string]interface{}
+		if err := dec.Decode(&m); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("%w: %v", ErrParseYAML, err)
+		}
+		if m != nil {
+			docs = append(docs, normalizeValue(m).(map[string]interface{}))
+		}
+	}
+	return docs, nil
+}
+
+// normalizeValue convierte los tipos propios de cada decodificador (json.Number, int64,
+// []map[string]interface{}, map[interface{}]interface{}) a los tipos que usa el árbol interno.
+func normalizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, v2 := range val {
+			val[k] = normalizeValue(v2)
+		}
+		return val
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, v2 := range val {
+			m[fmt.Sprint(k)] = normalizeValue(v2)
+		}
+		return m
+	case []interface{}:
+		for i, v2 := range val {
+			val[i] = normalizeValue(v2)
+		}
+		return val
+	case []map[string]interface{}:
+		s := make([]interface{}, len(val))
+		for i, v2 := range val {
+			s[i] = normalizeValue(v2)
+		}
+		return s
+	case json.Number:
+		if i, err := strconv.Atoi(val.String()); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case int64:
+		if int64(int(val)) == val {
+			return int(val)
+		}
+		return val
+	default:
+		return val
+	}
+}
+
+// setPath asigna un valor dentro del mapa creando los niveles intermedios necesarios.
+func setPath(m map[string]interface{}, keys []string, value interface{}) {
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[k] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = value
+}
+
+// parseEnv interpreta un archivo .env con líneas CLAVE=VALOR.
+// Admite comentarios (#), el prefijo "export" y valores entre comillas simples o dobles.
+func parseEnv(data []byte) (map[string]string, error) {
+	res := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: line %d: expected KEY=VALUE", ErrParseEnv, line)
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrParseEnv, line, err)
+			}
+			value = unquoted
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			// Los comentarios en línea solo aplican a valores sin comillas
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+		res[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParseEnv, err)
+	}
+	return res, nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Decoders
+// ------------------------------------------------------------------------------------------------
+
+// decoders contiene los conversores registrados con RegisterDecoder, por tipo de destino.
+var decoders = struct {
+	sync.RWMutex
+	m map[reflect.Type]func(interface{}) (interface{}, error)
+}{m: make(map[reflect.Type]func(interface{}) (interface{}, error))}
+
+// RegisterDecoder registra la conversión de un valor de la configuración al tipo T. Se aplica en
+// Get, GetOr y Unmarshal, también a los elementos de slices, mapas y campos de estructuras, y tiene
+// prioridad sobre las conversiones incluidas. Registrar de nuevo el mismo tipo reemplaza la anterior.
+//
+//	config.RegisterDecoder(func(v interface{}) (netip.Addr, error) {
+//		return netip.ParseAddr(fmt.Sprint(v))
+//	})
+func RegisterDecoder[T any](fn func(v interface{}) (T, error)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	decoders.Lock()
+	defer decoders.Unlock()
+	decoders.m[t] = func(v interface{}) (interface{}, error) {
+		return fn(v)
+	}
+}
+
+func lookupDecoder(t reflect.Type) (func(interface{}) (interface{}, error), bool) {
+	decoders.RLock()         // Bloqueo de lectura
+	defer decoders.RUnlock() // Liberar al salir
+	fn, ok := decoders.m[t]
+	return fn, ok
+}
+
+// ------------------------------------------------------------------------------------------------
+// Generic Accessors
+// ------------------------------------------------------------------------------------------------
+
+// Get devuelve el valor de la clave convertido a T con las mismas reglas que Unmarshal: escalares,
+// time.Duration, time.Time, url.URL, slices, mapas, estructuras y los tipos registrados con
+// RegisterDecoder. Retorna ErrKeyNotFound si la clave no existe y ErrTypeMismatch u ErrOverflow,
+// con la ruta del valor, si no es convertible.
+//
+//	ports, err := config.Get[[]uint16](cfg, "server.ports")
+func Get[T any](cfg IConfig, key string) (T, error) {
+	var out T
+	if key == "" {
+		return out, ErrKeyEmpty
+	}
+	if err := cfg.Unmarshal(key, &out); err != nil {
+		var zero T
+		return zero, err
+	}
+	return out, nil
+}
+
+// GetOr devuelve el valor de la clave convertido a T o def si no existe o no es convertible.
+func GetOr[T any](cfg IConfig, key string, def T) T {
+	v, err := Get[T](cfg, key)
+	if err != nil {
+		return def
+	}
+	return v
+}
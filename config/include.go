@@ -0,0 +1,262 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Las directivas solo se expanden en los archivos cargados con WithIncludes; en el resto son
+// claves comunes.
+const (
+	// IncludeKey, en la raíz de un archivo, lista otros archivos que se combinan antes que él, de
+	// modo que el archivo que los incluye tiene precedencia. Acepta una ruta, un patrón glob o una
+	// lista de ambos, relativos al directorio del archivo.
+	//
+	//	include:
+	//	  - database.yaml
+	//	  - services/*.yaml
+	IncludeKey = "include"
+
+	// RefKey reemplaza el mapa en el que aparece por el contenido del archivo referenciado. Las
+	// demás claves del mapa se combinan sobre ese contenido. Las referencias internas que inician
+	// con "#", como las de JSON Schema, se conservan sin cambios.
+	//
+	//	database:
+	//	  $ref: database.yaml
+	//	  pool: 20
+	RefKey = "$ref"
+)
+
+// WithIncludes habilita las directivas IncludeKey y RefKey en el archivo cargado con LoadFile,
+// LoadProfile o LoadFS y en los archivos que este incorpora. Reload y Watch las vuelven a expandir.
+func WithIncludes() LoadOption {
+	return func(s *source) {
+		s.includes = true
+	}
+}
+
+// ------------------------------------------------------------------------------------------------
+// Helpers
+// ------------------------------------------------------------------------------------------------
+
+// includer expande las directivas IncludeKey y RefKey de los archivos leídos con LoadFile,
+// LoadProfile y LoadFS. chain contiene las rutas absolutas de los archivos que se están expandiendo,
+// para detectar ciclos y reportar el origen de los errores.
+type includer struct {
+	fsys    fs.FS // Sistema de archivos de LoadFS; nil para el del sistema operativo
+	sep     string
+	merger  *merger
+	enabled bool // La fuente se cargó con WithIncludes
+
+	files []string // Archivos incorporados, en orden de lectura
+}
+
+func newIncluder(fsys fs.FS, sep string, s *source) *includer {
+	return &includer{fsys: fsys, sep: sep, merger: &merger{fallback: s.merge, keep: true}, enabled: s.includes}
+}
+
+// expand resuelve las directivas del mapa leído de path.
+func (in *includer) expand(path string, m map[string]interface{}, chain []string) (map[string]interface{}, error) {
+	dir := in.dir(path)
+
+	v, err := in.expandRefs(dir, m, chain)
+	if err != nil {
+		return nil, err
+	}
+	m = v.(map[string]interface{})
+
+	patterns, ok := m[IncludeKey]
+	if !ok {
+		return m, nil
+	}
+
+	var list []interface{}
+	switch p := patterns.(type) {
+	case string:
+		list = []interface{}{p}
+	case []interface{}:
+		list = p
+	default:
+		return nil, fmt.Errorf("%w: %s: %s must be a path or a list of paths", ErrInclude, formatChain(chain), IncludeKey)
+	}
+
+	res := make(map[string]interface{})
+	for _, p := range list {
+		pattern, ok := p.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s: %s must be a path or a list of paths", ErrInclude, formatChain(chain), IncludeKey)
+		}
+		included, err := in.read(dir, pattern, chain)
+		if err != nil {
+			return nil, err
+		}
+		in.merger.merge(res, included, nil)
+	}
+	in.merger.merge(res, omitKey(m, IncludeKey), nil)
+	return res, nil
+}
+
+// expandRefs reemplaza, en cualquier nivel, los mapas con RefKey por el contenido referenciado.
+func (in *includer) expandRefs(dir string, v interface{}, chain []string) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, v2 := range val {
+			if k == RefKey {
+				continue
+			}
+			r, err := in.expandRefs(dir, v2, chain)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = r
+		}
+
+		ref, ok := val[RefKey]
+		if !ok {
+			return res, nil
+		}
+		pattern, ok := ref.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s: %s must be a path", ErrInclude, formatChain(chain), RefKey)
+		}
+		if strings.HasPrefix(pattern, "#") {
+			res[RefKey] = pattern
+			return res, nil
+		}
+		content, err := in.read(dir, pattern, chain)
+		if err != nil {
+			return nil, err
+		}
+		in.merger.merge(content, res, nil)
+		return content, nil
+
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i, v2 := range val {
+			r, err := in.expandRefs(dir, v2, chain)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = r
+		}
+		return res, nil
+
+	default:
+		return val, nil
+	}
+}
+
+// read lee y combina, en orden alfabético, los archivos que coinciden con el patrón. Un patrón
+// sin comodines debe existir; uno con comodines puede no coincidir con ningún archivo.
+func (in *includer) read(dir, pattern string, chain []string) (map[string]interface{}, error) {
+	pattern = in.join(dir, pattern)
+
+	matches, err := in.glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInclude, formatChain(chain), err)
+	}
+	if len(matches) == 0 && !hasGlobMeta(pattern) {
+		matches = []string{pattern}
+	}
+
+	res := make(map[string]interface{})
+	for _, path := range matches {
+		abs, err := in.abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInclude, err)
+		}
+		next := appendSeg(chain, abs)
+		for _, p := range chain {
+			if p == abs {
+				return nil, fmt.Errorf("%w: %s", ErrIncludeCycle, formatChain(next))
+			}
+		}
+
+		data, err := in.readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrReadFile, formatChain(next), err)
+		}
+		in.files = append(in.files, path)
+		docs, err := in.parse(path, data, next)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			in.merger.merge(res, doc, nil)
+		}
+	}
+	return res, nil
+}
+
+// parse decodifica todos los documentos del archivo y, si están habilitadas, expande sus directivas.
+func (in *includer) parse(path string, data []byte, chain []string) ([]map[string]interface{}, error) {
+	docs, err := unmarshalDocuments(data, formatFromPath(path), in.sep)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", formatChain(chain), err)
+	}
+	if !in.enabled {
+		return docs, nil
+	}
+	for i, doc := range docs {
+		if docs[i], err = in.expand(path, doc, chain); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// ------------------------------------------------------------------------------------------------
+// File System
+// ------------------------------------------------------------------------------------------------
+
+func (in *includer) dir(p string) string {
+	if in.fsys != nil {
+		return path.Dir(p)
+	}
+	return filepath.Dir(p)
+}
+
+func (in *includer) join(dir, pattern string) string {
+	if in.fsys != nil {
+		return path.Join(dir, pattern)
+	}
+	if filepath.IsAbs(pattern) {
+		return pattern
+	}
+	return filepath.Join(dir, pattern)
+}
+
+func (in *includer) glob(pattern string) ([]string, error) {
+	if in.fsys != nil {
+		return fs.Glob(in.fsys, pattern)
+	}
+	return filepath.Glob(pattern)
+}
+
+func (in *includer) abs(p string) (string, error) {
+	if in.fsys != nil {
+		return path.Clean(p), nil
+	}
+	return filepath.Abs(p)
+}
+
+func (in *includer) readFile(p string) ([]byte, error) {
+	if in.fsys != nil {
+		return fs.ReadFile(in.fsys, p)
+	}
+	return os.ReadFile(p)
+}
+
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// formatChain representa la cadena de inclusión: a.yaml -> b.yaml -> c.yaml.
+func formatChain(chain []string) string {
+	return strings.Join(chain, " -> ")
+}
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"fmt"
+	"math"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Implementation Methods
+// ------------------------------------------------------------------------------------------------
+
+// GetInt64 devuelve el valor de la clave como int64.
+// Si no existe, no es convertible, tiene parte decimal o excede el rango de int64, retorna 0.
+func (c *Config) GetInt64(keys string) int64 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return scalarOf(c, keys, toInt64E)
+}
+
+// GetInt32 devuelve el valor de la clave como int32.
+// Si no existe, no es convertible, tiene parte decimal o excede el rango de int32, retorna 0.
+func (c *Config) GetInt32(keys string) int32 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return scalarOf(c, keys, toInt32E)
+}
+
+// GetUint64 devuelve el valor de la clave como uint64.
+// Si no existe, no es convertible, tiene parte decimal o excede el rango de uint64, retorna 0.
+func (c *Config) GetUint64(keys string) uint64 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return scalarOf(c, keys, toUint64E)
+}
+
+// GetUint devuelve el valor de la clave como uint.
+// Si no existe, no es convertible, tiene parte decimal o excede el rango de uint, retorna 0.
+func (c *Config) GetUint(keys string) uint {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return scalarOf(c, keys, toUintE)
+}
+
+// GetMapInt64 convierte cada valor del mapa a int64; los valores no convertibles quedan en 0.
+// Si la clave no existe o no es un mapa, retorna un mapa vacío.
+func (c *Config) GetMapInt64(keys string) map[string]int64 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return mapOf(c, keys, toInt64E)
+}
+
+// GetMapInt32 convierte cada valor del mapa a int32; los valores no convertibles quedan en 0.
+// Si la clave no existe o no es un mapa, retorna un mapa vacío.
+func (c *Config) GetMapInt32(keys string) map[string]int32 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return mapOf(c, keys, toInt32E)
+}
+
+// GetMapUint64 convierte cada valor del mapa a uint64; los valores no convertibles quedan en 0.
+// Si la clave no existe o no es un mapa, retorna un mapa vacío.
+func (c *Config) GetMapUint64(keys string) map[string]uint64 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return mapOf(c, keys, toUint64E)
+}
+
+// GetMapUint convierte cada valor del mapa a uint; los valores no convertibles quedan en 0.
+// Si la clave no existe o no es un mapa, retorna un mapa vacío.
+func (c *Config) GetMapUint(keys string) map[string]uint {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return mapOf(c, keys, toUintE)
+}
+
+// GetSliceInt64 convierte cada elemento del slice a int64; los no convertibles quedan en 0.
+// Si la clave no existe o no es un slice, retorna un slice vacío.
+func (c *Config) GetSliceInt64(keys string) []int64 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return sliceOf(c, keys, toInt64E)
+}
+
+// GetSliceInt32 convierte cada elemento del slice a int32; los no convertibles quedan en 0.
+// Si la clave no existe o no es un slice, retorna un slice vacío.
+func (c *Config) GetSliceInt32(keys string) []int32 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return sliceOf(c, keys, toInt32E)
+}
+
+// GetSliceUint64 convierte cada elemento del slice a uint64; los no convertibles quedan en 0.
+// Si la clave no existe o no es un slice, retorna un slice vacío.
+func (c *Config) GetSliceUint64(keys string) []uint64 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return sliceOf(c, keys, toUint64E)
+}
+
+// GetSliceUint convierte cada elemento del slice a uint; los no convertibles quedan en 0.
+// Si la clave no existe o no es un slice, retorna un slice vacío.
+func (c *Config) GetSliceUint(keys string) []uint {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return sliceOf(c, keys, toUintE)
+}
+
+// GetInt64E devuelve el valor de la clave como int64. Retorna ErrOverflow si excede el rango
+// y ErrTypeMismatch si no es un entero, incluidos los números con parte decimal.
+func (c *Config) GetInt64E(keys string) (int64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return 0, err
+	}
+	return convertE(keys, v, toInt64E)
+}
+
+// GetInt32E devuelve el valor de la clave como int32. Retorna ErrOverflow si excede el rango
+// y ErrTypeMismatch si no es un entero, incluidos los números con parte decimal.
+func (c *Config) GetInt32E(keys string) (int32, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return 0, err
+	}
+	return convertE(keys, v, toInt32E)
+}
+
+// GetUint64E devuelve el valor de la clave como uint64. Retorna ErrOverflow si excede el rango
+// y ErrTypeMismatch si no es un entero, incluidos los números con parte decimal.
+func (c *Config) GetUint64E(keys string) (uint64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return 0, err
+	}
+	return convertE(keys, v, toUint64E)
+}
+
+// GetUintE devuelve el valor de la clave como uint. Retorna ErrOverflow si excede el rango
+// y ErrTypeMismatch si no es un entero, incluidos los números con parte decimal.
+func (c *Config) GetUintE(keys string) (uint, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return 0, err
+	}
+	return convertE(keys, v, toUintE)
+}
+
+// GetMapInt64E convierte cada valor del mapa a int64, reportando la primera clave no convertible.
+func (c *Config) GetMapInt64E(keys string) (map[string]int64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, toInt64E)
+}
+
+// GetMapInt32E convierte cada valor del mapa a int32, reportando la primera clave no convertible.
+func (c *Config) GetMapInt32E(keys string) (map[string]int32, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, toInt32E)
+}
+
+// GetMapUint64E convierte cada valor del mapa a uint64, reportando la primera clave no convertible.
+func (c *Config) GetMapUint64E(keys string) (map[string]uint64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, toUint64E)
+}
+
+// GetMapUintE convierte cada valor del mapa a uint, reportando la primera clave no convertible.
+func (c *Config) GetMapUintE(keys string) (map[string]uint, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, toUintE)
+}
+
+// GetSliceInt64E convierte cada elemento del slice a int64, reportando el primer índice no convertible.
+func (c *Config) GetSliceInt64E(keys string) ([]int64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, toInt64E)
+}
+
+// GetSliceInt32E convierte cada elemento del slice a int32, reportando el primer índice no convertible.
+func (c *Config) GetSliceInt32E(keys string) ([]int32, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, toInt32E)
+}
+
+// GetSliceUint64E convierte cada elemento del slice a uint64, reportando el primer índice no convertible.
+func (c *Config) GetSliceUint64E(keys string) ([]uint64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, toUint64E)
+}
+
+// GetSliceUintE convierte cada elemento del slice a uint, reportando el primer índice no convertible.
+func (c *Config) GetSliceUintE(keys string) ([]uint, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, toUintE)
+}
+
+// ------------------------------------------------------------------------------------------------
+// Helpers
+// ------------------------------------------------------------------------------------------------
+
+// toInt32E convierte un valor a int32 detectando el desbordamiento.
+func toInt32E(value interface{}) (int32, error) {
+	i, err := toInt64E(value)
+	if err != nil {
+		return 0, err
+	}
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: %d does not fit in int32", ErrOverflow, i)
+	}
+	return int32(i), nil
+}
+
+// toUintE convierte un valor a uint detectando el desbordamiento en plataformas de 32 bits.
+func toUintE(value interface{}) (uint, error) {
+	u, err := toUint64E(value)
+	if err != nil {
+		return 0, err
+	}
+	if u > math.MaxUint {
+		return 0, fmt.Errorf("%w: %d does not fit in uint", ErrOverflow, u)
+	}
+	return uint(u), nil
+}
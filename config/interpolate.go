@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	interpolationOpen    = "${"
+	interpolationClose   = "}"
+	interpolationDefault = ":-"
+)
+
+// resolve aplica la interpolación y los resolvers de secretos a un valor leído de la
+// configuración, recorriendo mapas y slices. Si ningún string contiene referencias se retorna
+// el mismo valor sin copiarlo. Debe llamarse con el bloqueo de lectura tomado.
+func (c *Config) resolve(v interface{}) (interface{}, error) {
+	if !c.opts.Interpolate && len(c.resolvers) == 0 {
+		return v, nil
+	}
+	r, _, err := c.resolveValue(v, nil)
+	return r, err
+}
+
+// interpolator retorna la función con la que el esquema valida los valores de data: resuelve
+// las referencias ${...} contra el propio data, que aún no es la vista publicada, sin aplicar los
+// resolvers de secretos para no exponerlos en los mensajes de error. Si la interpolación falla, el
+// valor se valida tal como está. Retorna nil si la interpolación no está habilitada.
+func (c *Config) interpolator(data map[string]interface{}) func(interface{}) interface{} {
+	if !c.opts.Interpolate {
+		return nil
+	}
+	view := &Config{data: data, opts: c.opts}
+	return func(v interface{}) interface{} {
+		if r, err := view.resolve(v); err == nil {
+			return r
+		}
+		return v
+	}
+}
+
+// resolveValue retorna el valor resuelto e indica si difiere del original, para copiar
+// únicamente los mapas y slices que contienen referencias.
+func (c *Config) resolveValue(v interface{}, stack []string) (interface{}, bool, error) {
+	switch val := v.(type) {
+	case string:
+		return c.resolveString(val, stack)
+	case map[string]interface{}:
+		var cp map[string]interface{}
+		for k, v2 := range val {
+			r, changed, err := c.resolveValue(v2, stack)
+			if err != nil {
+				return nil, false, err
+			}
+			if !changed {
+				continue
+			}
+			if cp == nil {
+				cp = make(map[string]interface{}, len(val))
+				for k2, v3 := range val {
+					cp[k2] = v3
+				}
+			}
+			cp[k] = r
+		}
+		if cp == nil {
+			return val, false, nil
+		}
+		return cp, true, nil
+	case []interface{}:
+		var cp []interface{}
+		for i, v2 := range val {
+			r, changed, err := c.resolveValue(v2, stack)
+			if err != nil {
+				return nil, false, err
+			}
+			if !changed {
+				continue
+			}
+			if cp == nil {
+				cp = make([]interface{}, len(val))
+				copy(cp, val)
+			}
+			cp[i] = r
+		}
+		if cp == nil {
+			return val, false, nil
+		}
+		return cp, true, nil
+	default:
+		return val, false, nil
+	}
+}
+
+// resolveString interpola el string y, si el resultado coincide con el prefijo de un resolver
+// registrado, lo reemplaza por el secreto.
+func (c *Config) resolveString(s string, stack []string) (interface{}, bool, error) {
+	var r interface{} = s
+	changed := false
+
+	if c.opts.Interpolate && strings.Contains(s, interpolationOpen) {
+		var err error
+		if r, err = c.interpolate(s, stack); err != nil {
+			return nil, false, err
+		}
+		changed = true
+	}
+
+	if str, ok := r.(string); ok {
+		if resolver, ref := c.secretResolver(str); resolver != nil {
+			secret, err := resolver.Resolve(ref)
+			if err != nil {
+				return nil, false, err
+			}
+			return secret, true, nil
+		}
+	}
+	return r, changed, nil
+}
+
+// interpolate reemplaza cada ${NOMBRE} por el valor de la clave NOMBRE o, si no existe, por la
+// variable de entorno NOMBRE. ${NOMBRE:-valor} usa el valor indicado cuando ninguna está definida
+// y $${ produce un "${" literal. Si el string es una única referencia, se conserva el tipo del
+// valor referenciado. Las referencias circulares entre claves retornan ErrInterpolationCycle.
+func (c *Config) interpolate(s string, stack []string) (interface{}, error) {
+	var sb strings.Builder
+	rest := s
+	for {
+		i := strings.Index(rest, interpolationOpen)
+		if i < 0 {
+			sb.WriteString(rest)
+			break
+		}
+		if i > 0 && rest[i-1] == '$' {
+			sb.WriteString(rest[:i-1] + interpolationOpen)
+			rest = rest[i+len(interpolationOpen):]
+			continue
+		}
+
+		end := strings.Index(rest[i:], interpolationClose)
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unterminated reference in %q", ErrInterpolation, s)
+		}
+		expr := rest[i+len(interpolationOpen) : i+end]
+
+		v, err := c.lookupReference(expr, stack)
+		if err != nil {
+			return nil, err
+		}
+		// Una referencia que ocupa todo el string conserva el tipo original
+		if rest == s && i == 0 && end == len(s)-1 {
+			return v, nil
+		}
+		sb.WriteString(rest[:i])
+		sb.WriteString(c.opts.StringPolicy.toString(v))
+		rest = rest[i+end+1:]
+	}
+	return sb.String(), nil
+}
+
+// lookupReference resuelve el contenido de una referencia: clave de configuración, variable
+// de entorno o valor por defecto, en ese orden.
+func (c *Config) lookupReference(expr string, stack []string) (interface{}, error) {
+	name, def, hasDefault := strings.Cut(expr, interpolationDefault)
+	name = strings.TrimSpace(name)
+
+	for _, k := range stack {
+		if k == name {
+			return nil, fmt.Errorf("%w: %s -> %s", ErrInterpolationCycle, strings.Join(stack, " -> "), name)
+		}
+	}
+
+	if v, ok := lookup(c.refsData(), splitKey(name, c.opts.Separator)); ok && name != "" {
+		r, _, err := c.resolveValue(v, appendSeg(stack, name))
+		return r, err
+	}
+	if v, ok := os.LookupEnv(name); ok && v != "" {
+		return v, nil
+	}
+	if hasDefault {
+		return c.interpolate(def, stack)
+	}
+	return "", nil
+}
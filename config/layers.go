@@ -0,0 +1,509 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Layer
+// ------------------------------------------------------------------------------------------------
+
+// Layer identifica el origen de un conjunto de valores. Las capas se combinan en orden
+// ascendente, por lo que una capa mayor sobrescribe a las menores sin importar el orden de carga.
+type Layer int
+
+const (
+	LayerDefaults Layer = iota
+	LayerFile
+	LayerRemote
+	LayerEnv
+	LayerFlags
+	LayerRuntime
+)
+
+var layerLabels = map[Layer]string{
+	LayerDefaults: "defaults",
+	LayerFile:     "file",
+	LayerRemote:   "remote",
+	LayerEnv:      "env",
+	LayerFlags:    "flags",
+	LayerRuntime:  "runtime",
+}
+
+func (l Layer) String() string {
+	if label, ok := layerLabels[l]; ok {
+		return label
+	}
+	return fmt.Sprintf("Layer(%d)", int(l))
+}
+
+// Origin describe de dónde proviene el valor efectivo de una clave.
+// Source contiene la ruta del archivo, el nombre del proveedor, el prefijo del entorno o el nombre
+// del FlagSet según la capa.
+type Origin struct {
+	Layer  Layer
+	Source string
+}
+
+func (o Origin) String() string {
+	if o.Source == "" {
+		return o.Layer.String()
+	}
+	return o.Layer.String() + ":" + o.Source
+}
+
+// source es un conjunto de valores cargado dentro de una capa.
+// Su mapa no se modifica una vez registrado: cualquier cambio genera un mapa nuevo.
+type source struct {
+	layer Layer
+	name  string
+	path  string        // Archivo del que se leyó, usado por Reload y Watch
+	merge MergeStrategy // Estrategia para los slices de la fuente
+	data  map[string]interface{}
+
+	profiles []string   // Perfiles activos si el archivo se cargó con LoadProfile; nil en otro caso
+	includes bool       // Expande las directivas include y $ref (WithIncludes)
+	files    []string   // Archivos incorporados con include y $ref en la última lectura
+	patches  []patch    // Escrituras por índice de SetDefault y Set, aplicadas sobre la vista combinada
+	replaced [][]string // Claves cuyos valores de las fuentes anteriores se descartan (Delete y Replace)
+	version  string     // Versión del contenido de un proveedor (ETag o hash), para consultas condicionales
+}
+
+// patch es una escritura que atraviesa un slice aportado por otras fuentes, como
+// Set("servers.1.host", "c"). Se guarda aparte del mapa de la fuente y se aplica sobre el slice
+// combinado, de modo que el resto del slice sigue tomando sus valores de las capas inferiores.
+// Un patch con DeleteDirective como valor elimina la clave; estos se aplican antes que el resto,
+// cuyos índices ya consideran los elementos eliminados.
+type patch struct {
+	keys  []string
+	value interface{}
+}
+
+// newSource crea una fuente aplicando las opciones de carga.
+func newSource(layer Layer, name, path string, data map[string]interface{}, opts []LoadOption) *source {
+	s := &source{layer: layer, name: name, path: path, data: data}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ------------------------------------------------------------------------------------------------
+// Implementation Methods
+// ------------------------------------------------------------------------------------------------
+
+// Origin indica la capa y la fuente que aportan el valor efectivo de la clave.
+// Si la clave no existe, retorna false.
+func (c *Config) Origin(key string) (Origin, bool) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+
+	if key == "" {
+		return Origin{}, false
+	}
+	keys := splitKey(key, c.opts.Separator)
+
+	for i := len(c.sources) - 1; i >= 0; i-- {
+		s := c.sources[i]
+		// Una directiva de eliminación oculta los valores de las fuentes anteriores
+		if deletedIn(s.data, keys) {
+			return Origin{}, false
+		}
+		if _, ok := lookup(s.data, keys); ok || s.patched(c.data, keys) {
+			return Origin{Layer: s.layer, Source: s.name}, true
+		}
+		// Delete y Replace descartan los valores de las fuentes anteriores
+		if s.replaces(keys) {
+			return Origin{}, false
+		}
+	}
+	return Origin{}, false
+}
+
+// deletedIn indica si la fuente contiene la directiva de eliminación en la clave o en alguno de
+// sus prefijos.
+func deletedIn(data map[string]interface{}, keys []string) bool {
+	for i := 1; i <= len(keys); i++ {
+		v, ok := lookup(data, keys[:i])
+		if !ok {
+			return false
+		}
+		if v == DeleteDirective {
+			return true
+		}
+	}
+	return false
+}
+
+// addSource registra la fuente en su capa y recalcula la vista combinada.
+// Una fuente con nombre que ya existe en la misma capa se reemplaza conservando su posición;
+// las fuentes sin nombre de las capas de valores por defecto y de ejecución son únicas.
+func (c *Config) addSource(s *source) error {
+	if err := s.merge.validate(); err != nil {
+		return err
+	}
+	return c.update(func(sources []*source) ([]*source, error) {
+		return withSource(sources, s), nil
+	})
+}
+
+// update aplica un cambio sobre las fuentes, recalcula la vista combinada y, ya sin el bloqueo,
+// notifica a los suscriptores de las claves cuyo valor cambió. Si fn falla, o el resultado no
+// cumple el esquema asociado, no se modifica nada.
+func (c *Config) update(fn func(sources []*source) ([]*source, error)) error {
+	c.mu.Lock()
+	if c.readOnly {
+		c.mu.Unlock()
+		return ErrReadOnly
+	}
+	rules := c.rules // fn puede cambiar las estrategias; se restauran si el cambio se descarta
+	sources, err := fn(c.sources)
+	if err != nil {
+		c.rules = rules
+		c.mu.Unlock()
+		return err
+	}
+
+	data := mergeSources(sources, c.rules)
+	if c.schema != nil {
+		if err := c.schema.validate(data, c.opts.Separator, c.interpolator(data)); err != nil {
+			c.rules = rules
+			c.mu.Unlock()
+			return err
+		}
+	}
+
+	old := c.data
+	c.sources, c.data = sources, data
+	changes := c.changes(old, c.data)
+	c.mu.Unlock()
+
+	for _, ch := range changes {
+		ch.fn(ch.old, ch.new)
+	}
+	return nil
+}
+
+// withSource retorna una copia de las fuentes con la nueva fuente insertada al final de su capa,
+// o reemplazando a la fuente con el mismo nombre y capa.
+func withSource(sources []*source, s *source) []*source {
+	res := make([]*source, 0, len(sources)+1)
+	inserted := false
+
+	for _, cur := range sources {
+		if !inserted && (s.name != "" || isEditable(s.layer)) && cur.layer == s.layer && cur.name == s.name {
+			res = append(res, s)
+			inserted = true
+			continue
+		}
+		if !inserted && cur.layer > s.layer {
+			res = append(res, s)
+			inserted = true
+		}
+		res = append(res, cur)
+	}
+
+	if !inserted {
+		res = append(res, s)
+	}
+	return res
+}
+
+// isEditable indica si la capa admite cambios clave por clave (SetDefault y Set).
+func isEditable(layer Layer) bool {
+	return layer == LayerDefaults || layer == LayerRuntime
+}
+
+// layerSource retorna una copia de la fuente sin nombre de la capa, lista para ser modificada.
+func layerSource(sources []*source, layer Layer) *source {
+	for _, s := range sources {
+		if s.layer == layer && s.name == "" {
+			cp := *s
+			cp.data = cloneValue(s.data).(map[string]interface{})
+			return &cp
+		}
+	}
+	return &source{layer: layer, data: make(map[string]interface{})}
+}
+
+// write escribe el valor en la fuente. Si la ruta atraviesa un slice de la vista combinada que la
+// fuente no contiene, la escritura se guarda como un patch en lugar de copiar el slice completo.
+// Los patches que quedan dentro de la ruta escrita se descartan, salvo las eliminaciones, que se
+// aplican antes que la escritura.
+func (s *source) write(merged map[string]interface{}, keys []string, value interface{}) error {
+	patches := make([]patch, 0, len(s.patches)+1)
+	for _, p := range s.patches {
+		if !hasPrefix(p.keys, keys) || p.value == DeleteDirective {
+			patches = append(patches, p)
+		}
+	}
+
+	if n := slicePrefix(merged, keys); n > 0 {
+		if _, ok := lookupSlice(s.data, keys[:n]); !ok {
+			if err := checkIndexes(merged, keys); err != nil {
+				return err
+			}
+			s.patches = append(patches, patch{keys: keys, value: value})
+			return nil
+		}
+	}
+	s.patches = patches
+	return assign(s.data, keys, value)
+}
+
+// unset quita de la fuente el valor de la clave junto con los patches que la contienen o que
+// están dentro de ella y las marcas de Delete y Replace de la clave. Si la ruta atraviesa un slice
+// de la propia fuente, se quita el slice completo, ya que la fuente lo reemplaza entero en la vista
+// combinada.
+func (s *source) unset(merged map[string]interface{}, keys []string) {
+	var deletes []int
+	patches := make([]patch, 0, len(s.patches))
+	for _, p := range s.patches {
+		if hasPrefix(p.keys, keys) || hasPrefix(keys, p.keys) {
+			if p.value != DeleteDirective {
+				continue
+			}
+			deletes = append(deletes, len(patches))
+		}
+		patches = append(patches, p)
+	}
+	// Las eliminaciones se deshacen de la última a la primera para restaurar los índices
+	for i := len(deletes) - 1; i >= 0; i-- {
+		patches = restorePatches(merged, patches, deletes[i])
+	}
+	s.patches = patches
+	s.replaced = withoutPaths(s.replaced, keys)
+
+	if n := slicePrefix(s.data, keys); n > 0 {
+		keys = keys[:n]
+	}
+	remove(s.data, keys)
+}
+
+// drop registra en la fuente la eliminación de la clave de la vista combinada, sin modificar las
+// fuentes anteriores. Si la ruta atraviesa un slice que la fuente no contiene, se guarda un patch de
+// eliminación y los patches de la fuente se ajustan a los índices resultantes; en otro caso, la
+// clave se quita de la fuente y, fuera de sus slices, se marca con clear.
+func (s *source) drop(merged map[string]interface{}, keys []string) {
+	n := slicePrefix(merged, keys)
+	if n == 0 {
+		s.clear(keys)
+		return
+	}
+	if _, ok := lookupSlice(s.data, keys[:n]); ok {
+		remove(s.data, keys)
+		return
+	}
+
+	var patches []patch
+	for _, p := range s.patches {
+		if p.value == DeleteDirective {
+			patches = append(patches, p)
+		}
+	}
+	for _, p := range withoutPatchKey(s.patches, elementPrefix(merged, keys), keys) {
+		if p.value != DeleteDirective {
+			patches = append(patches, p)
+		}
+	}
+	s.patches = append(patches, patch{keys: keys, value: DeleteDirective})
+}
+
+// clear quita la clave de la fuente y la marca para que la vista combinada descarte los valores
+// que aportan las fuentes anteriores. Replace la usa antes de escribir la nueva sección.
+func (s *source) clear(keys []string) {
+	patches := make([]patch, 0, len(s.patches))
+	for _, p := range s.patches {
+		if !hasPrefix(p.keys, keys) {
+			patches = append(patches, p)
+		}
+	}
+	s.patches = patches
+	s.replaced = append(withoutPaths(s.replaced, keys), keys)
+	remove(s.data, keys)
+}
+
+// replaces indica si la fuente descarta los valores anteriores de la clave o de alguno de sus
+// prefijos.
+func (s *source) replaces(keys []string) bool {
+	for _, r := range s.replaced {
+		if hasPrefix(keys, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// patched indica si alguno de los patches de la fuente aporta el valor de la clave.
+func (s *source) patched(merged map[string]interface{}, keys []string) bool {
+	if _, ok := lookup(merged, keys); !ok {
+		return false
+	}
+	for _, p := range s.patches {
+		if p.value != DeleteDirective && (hasPrefix(keys, p.keys) || hasPrefix(p.keys, keys)) {
+			return true
+		}
+	}
+	return false
+}
+
+// restorePatches quita el patch de eliminación i. Si eliminaba un elemento de un slice, desplaza
+// los índices de las eliminaciones posteriores y de las escrituras, que ya consideraban su ausencia.
+func restorePatches(merged map[string]interface{}, patches []patch, i int) []patch {
+	d := patches[i]
+	res := append(patches[:i:i], patches[i+1:]...)
+	n := elementPrefix(merged, d.keys)
+	q, err := strconv.Atoi(d.keys[len(d.keys)-1])
+	if n == 0 || err != nil {
+		return res
+	}
+
+	// q es la posición del elemento restaurado en el slice tras cada eliminación
+	shift := func(j, idx int) {
+		p := res[j]
+		p.keys = append([]string(nil), p.keys...)
+		p.keys[n] = strconv.Itoa(idx + 1)
+		res[j] = p
+	}
+	for _, deletes := range []bool{true, false} {
+		for j, p := range res {
+			if (p.value == DeleteDirective) != deletes || (deletes && j < i) || len(p.keys) <= n || !hasPrefix(p.keys, d.keys[:n]) {
+				continue
+			}
+			idx, err := strconv.Atoi(p.keys[n])
+			switch {
+			case err != nil:
+			case idx >= q:
+				shift(j, idx)
+			case deletes && len(p.keys) == n+1:
+				q--
+			}
+		}
+	}
+	return res
+}
+
+// elementPrefix retorna la longitud de la ruta del slice si la clave apunta a un elemento de un
+// slice de la vista combinada, o 0 en otro caso.
+func elementPrefix(merged map[string]interface{}, keys []string) int {
+	n := len(keys) - 1
+	if _, ok := lookupSlice(merged, keys[:n]); !ok || n == 0 {
+		return 0
+	}
+	return n
+}
+
+// withoutPaths retorna una copia de las rutas sin las que quedan dentro de la clave.
+func withoutPaths(paths [][]string, keys []string) [][]string {
+	res := make([][]string, 0, len(paths)+1)
+	for _, r := range paths {
+		if !hasPrefix(r, keys) {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// target es la ruta, relativa a una fuente, que corresponde a una clave de la vista combinada.
+type target struct {
+	source int
+	keys   []string
+}
+
+// keyTargets traduce la clave de la vista combinada a las rutas de cada fuente que la aportan.
+func keyTargets(sources []*source, rules []mergeRule, merged map[string]interface{}, keys []string) []target {
+	n := slicePrefix(merged, keys)
+	if n == 0 {
+		res := make([]target, len(sources))
+		for i := range sources {
+			res[i] = target{source: i, keys: keys}
+		}
+		return res
+	}
+
+	owners := sliceOwners(sources, rules, keys[:n])
+	idx, err := strconv.Atoi(keys[n])
+	if err != nil || idx < 0 || idx >= len(owners) {
+		return nil
+	}
+
+	res := make([]target, 0, len(owners[idx]))
+	for _, o := range owners[idx] {
+		local := append(append(append(make([]string, 0, len(keys)), keys[:n]...), strconv.Itoa(o.index)), keys[n+1:]...)
+		res = append(res, target{source: o.source, keys: local})
+	}
+	return res
+}
+
+// withoutPatchKey ajusta los patches a la eliminación de la clave: se descartan los que quedan
+// dentro de ella, se quita la clave del valor de los que la contienen y, si se eliminó un elemento
+// del slice de longitud n, se desplazan los índices de los elementos siguientes.
+func withoutPatchKey(patches []patch, n int, keys []string) []patch {
+	res := make([]patch, 0, len(patches))
+	for _, p := range patches {
+		switch {
+		case hasPrefix(p.keys, keys):
+			continue
+		case hasPrefix(keys, p.keys):
+			root := map[string]interface{}{"": cloneValue(p.value)}
+			remove(root, append([]string{""}, keys[len(p.keys):]...))
+			p.value = root[""]
+		case n > 0 && len(keys) == n+1 && len(p.keys) > n && hasPrefix(p.keys, keys[:n]):
+			deleted, _ := strconv.Atoi(keys[n])
+			if idx, err := strconv.Atoi(p.keys[n]); err == nil && idx > deleted {
+				p.keys = append([]string(nil), p.keys...)
+				p.keys[n] = strconv.Itoa(idx - 1)
+			}
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+// mergeSources combina todas las fuentes en orden de precedencia sobre un mapa nuevo, aplicando
+// las estrategias de cada fuente y las registradas por clave. Los slices establecidos con Set
+// reemplazan siempre; las escrituras por índice se aplican como patches sobre el slice combinado.
+func mergeSources(sources []*source, rules []mergeRule) map[string]interface{} {
+	data := make(map[string]interface{})
+	for _, s := range sources {
+		for _, r := range s.replaced {
+			data = discard(data, r)
+		}
+
+		m := &merger{rules: rules, fallback: s.merge}
+		if s.layer == LayerRuntime {
+			m.rules = nil
+		}
+		m.merge(data, cloneValue(s.data).(map[string]interface{}), nil)
+
+		// Si el slice ya no existe o es más corto, el patch se ignora
+		for _, deletes := range []bool{true, false} {
+			for _, p := range s.patches {
+				if (p.value == DeleteDirective) != deletes || slicePrefix(data, p.keys) == 0 || checkIndexes(data, p.keys) != nil {
+					continue
+				}
+				if deletes {
+					remove(data, p.keys)
+				} else {
+					_ = assign(data, p.keys, cloneValue(p.value))
+				}
+			}
+		}
+	}
+	return data
+}
+
+// discard quita la clave de la vista combinada si su padre es un mapa. Una ruta vacía, que solo
+// existe en las vistas Sub, descarta la vista completa.
+func discard(data map[string]interface{}, keys []string) map[string]interface{} {
+	if len(keys) == 0 {
+		return make(map[string]interface{})
+	}
+	if parent, ok := lookup(data, keys[:len(keys)-1]); ok {
+		if m, isMap := parent.(map[string]interface{}); isMap {
+			delete(m, keys[len(keys)-1])
+		}
+	}
+	return data
+}
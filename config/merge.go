@@ -0,0 +1,331 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DeleteDirective, usado como valor en una fuente, elimina la clave heredada de las fuentes
+// anteriores en lugar de reemplazarla.
+//
+//	cors:
+//	  allowed_origins: $delete
+const DeleteDirective = "$delete"
+
+const mergeByKeyPrefix = "merge-by-key:"
+
+// ------------------------------------------------------------------------------------------------
+// MergeStrategy
+// ------------------------------------------------------------------------------------------------
+
+// MergeStrategy define cómo se combina un slice con el slice de la misma clave en las fuentes
+// anteriores. Los mapas siempre se combinan clave por clave y los escalares se reemplazan.
+type MergeStrategy string
+
+const (
+	MergeReplace      MergeStrategy = "replace"       // El slice reemplaza al anterior (por defecto)
+	MergeAppend       MergeStrategy = "append"        // Los elementos se agregan al final
+	MergeAppendUnique MergeStrategy = "append-unique" // Se agregan solo los elementos que no existen
+)
+
+// MergeByKey combina listas de objetos usando el campo indicado como identificador: los objetos
+// con el mismo valor se combinan entre sí y los nuevos se agregan al final.
+func MergeByKey(field string) MergeStrategy {
+	return MergeStrategy(mergeByKeyPrefix + field)
+}
+
+func (s MergeStrategy) validate() error {
+	switch s {
+	case "", MergeReplace, MergeAppend, MergeAppendUnique:
+		return nil
+	}
+	if field, ok := strings.CutPrefix(string(s), mergeByKeyPrefix); ok && field != "" {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrMergeStrategy, string(s))
+}
+
+// LoadOption ajusta cómo se combina una fuente cargada con LoadFile o LoadBytes.
+type LoadOption func(s *source)
+
+// WithMergeStrategy define la estrategia para todos los slices de la fuente. Las estrategias
+// registradas por clave con SetMergeStrategy tienen prioridad.
+func WithMergeStrategy(strategy MergeStrategy) LoadOption {
+	return func(s *source) {
+		s.merge = strategy
+	}
+}
+
+// mergeRule asocia una estrategia a una clave; admite el segmento "*".
+type mergeRule struct {
+	keys     []string
+	strategy MergeStrategy
+}
+
+// ------------------------------------------------------------------------------------------------
+// Implementation Methods
+// ------------------------------------------------------------------------------------------------
+
+// SetMergeStrategy define la estrategia con la que se combina el slice de la clave entre todas las
+// fuentes, sin importar con qué opciones se cargaron; los valores establecidos con Set siempre
+// reemplazan. La vista combinada se recalcula de inmediato.
+//
+//	cfg.SetMergeStrategy("cors.allowed_origins", config.MergeAppendUnique)
+//	cfg.SetMergeStrategy("middlewares", config.MergeByKey("name"))
+func (c *Config) SetMergeStrategy(key string, strategy MergeStrategy) error {
+	if key == "" {
+		return ErrKeyEmpty
+	}
+	if err := strategy.validate(); err != nil {
+		return err
+	}
+	keys := splitKey(key, c.opts.Separator)
+
+	return c.update(func(sources []*source) ([]*source, error) {
+		rules := make([]mergeRule, 0, len(c.rules)+1)
+		for _, r := range c.rules {
+			if !reflect.DeepEqual(r.keys, keys) {
+				rules = append(rules, r)
+			}
+		}
+		c.rules = append(rules, mergeRule{keys: keys, strategy: strategy})
+		return sources, nil
+	})
+}
+
+// ------------------------------------------------------------------------------------------------
+// Helpers
+// ------------------------------------------------------------------------------------------------
+
+// merger combina una fuente sobre la vista acumulada aplicando las estrategias.
+type merger struct {
+	rules    []mergeRule
+	fallback MergeStrategy // Estrategia de la fuente que se está combinando
+
+	// keep conserva las directivas de eliminación en lugar de aplicarlas. Se usa al combinar
+	// partes de una misma fuente (documentos, archivos incluidos, perfiles), para que la
+	// directiva elimine también la clave de las fuentes anteriores.
+	keep bool
+}
+
+func (m *merger) strategy(path []string) MergeStrategy {
+	for i := len(m.rules) - 1; i >= 0; i-- {
+		if matchPath(m.rules[i].keys, path) {
+			return m.rules[i].strategy
+		}
+	}
+	return m.fallback
+}
+
+// merge combina src sobre dst. Los mapas de src que no existen en dst se copian aplicando las
+// directivas de eliminación que contengan.
+func (m *merger) merge(dst, src map[string]interface{}, path []string) {
+	for k, srcVal := range src {
+		if srcVal == DeleteDirective {
+			if m.keep {
+				dst[k] = DeleteDirective
+			} else {
+				delete(dst, k)
+			}
+			continue
+		}
+		keyPath := appendSeg(path, k)
+
+		switch val := srcVal.(type) {
+		case map[string]interface{}:
+			dstMap, ok := dst[k].(map[string]interface{})
+			if !ok {
+				dstMap = make(map[string]interface{}, len(val))
+				dst[k] = dstMap
+			}
+			m.merge(dstMap, val, keyPath)
+		case []interface{}:
+			dstSlice, ok := dst[k].([]interface{})
+			if !ok {
+				dst[k] = val
+				continue
+			}
+			dst[k] = m.mergeSlice(dstSlice, val, keyPath)
+		default:
+			dst[k] = srcVal
+		}
+	}
+}
+
+func (m *merger) mergeSlice(dst, src []interface{}, path []string) []interface{} {
+	strategy := m.strategy(path)
+	switch strategy {
+	case MergeAppend:
+		return append(dst[:len(dst):len(dst)], src...)
+	case MergeAppendUnique:
+		res := dst[:len(dst):len(dst)]
+		for _, v := range src {
+			if !containsValue(res, v) {
+				res = append(res, v)
+			}
+		}
+		return res
+	}
+
+	field, ok := strings.CutPrefix(string(strategy), mergeByKeyPrefix)
+	if !ok {
+		return src
+	}
+
+	res := dst[:len(dst):len(dst)]
+	for _, v := range src {
+		item, isMap := v.(map[string]interface{})
+		id, hasID := item[field]
+		idx := -1
+		if isMap && hasID {
+			idx = indexByField(res, field, id)
+		}
+		if idx < 0 {
+			res = append(res, v)
+			continue
+		}
+		merged := cloneValue(res[idx]).(map[string]interface{})
+		m.merge(merged, item, appendSeg(path, strconv.Itoa(idx)))
+		res[idx] = merged
+	}
+	return res
+}
+
+// combineDocuments combina en orden los documentos de una misma fuente, conservando las
+// directivas de eliminación para que se apliquen también sobre las fuentes anteriores.
+func combineDocuments(docs []map[string]interface{}, strategy MergeStrategy) map[string]interface{} {
+	if len(docs) == 1 {
+		return docs[0]
+	}
+	res := make(map[string]interface{})
+	m := &merger{fallback: strategy, keep: true}
+	for _, doc := range docs {
+		m.merge(res, doc, nil)
+	}
+	return res
+}
+
+// owner identifica el elemento de una fuente que aportó un elemento del slice combinado.
+type owner struct {
+	source int // Posición de la fuente
+	index  int // Índice del elemento en el slice de la fuente
+}
+
+// sliceOwners repite la combinación del slice de la ruta y retorna, para cada elemento del slice
+// combinado, los elementos de las fuentes que lo aportaron. Con MergeByKey un elemento puede
+// provenir de varias fuentes; un elemento reemplazado completo por un patch no tiene dueños.
+func sliceOwners(sources []*source, rules []mergeRule, path []string) [][]owner {
+	var (
+		values []interface{}
+		owners [][]owner
+	)
+
+	for i, s := range sources {
+		v, ok := lookup(s.data, path)
+		src, isSlice := v.([]interface{})
+		if s.replaces(path) || (ok && !isSlice) || (!ok && replacesPath(s.data, path)) {
+			// Delete, Replace, un escalar, un mapa o la directiva de eliminación reemplazan al slice
+			values, owners = nil, nil
+		}
+		if isSlice {
+			m := &merger{rules: rules, fallback: s.merge}
+			strategy := MergeReplace
+			if s.layer != LayerRuntime {
+				strategy = m.strategy(path)
+			}
+			field, byKey := strings.CutPrefix(string(strategy), mergeByKeyPrefix)
+			if !byKey && strategy != MergeAppend && strategy != MergeAppendUnique {
+				values, owners = nil, nil
+			}
+
+			for j, e := range src {
+				own := owner{source: i, index: j}
+				if strategy == MergeAppendUnique && containsValue(values, e) {
+					continue
+				}
+				if item, isMap := e.(map[string]interface{}); byKey && isMap {
+					if id, hasID := item[field]; hasID {
+						if idx := indexByField(values, field, id); idx >= 0 {
+							merged := cloneValue(values[idx]).(map[string]interface{})
+							m.merge(merged, item, appendSeg(path, strconv.Itoa(idx)))
+							values[idx] = merged
+							owners[idx] = append(owners[idx], own)
+							continue
+						}
+					}
+				}
+				values = append(values, e)
+				owners = append(owners, []owner{own})
+			}
+		}
+
+		// Los patches que eliminan o reemplazan un elemento completo cambian sus dueños
+		for _, deletes := range []bool{true, false} {
+			for _, p := range s.patches {
+				if (p.value == DeleteDirective) != deletes || len(p.keys) != len(path)+1 || !hasPrefix(p.keys, path) {
+					continue
+				}
+				idx, err := strconv.Atoi(p.keys[len(path)])
+				if err != nil || idx < 0 || idx >= len(owners) {
+					continue
+				}
+				if deletes {
+					values = append(values[:idx:idx], values[idx+1:]...)
+					owners = append(owners[:idx:idx], owners[idx+1:]...)
+				} else {
+					values[idx], owners[idx] = p.value, nil
+				}
+			}
+		}
+	}
+	return owners
+}
+
+// replacesPath indica si la fuente reemplaza la ruta con un valor que no es un mapa en alguno de
+// sus prefijos, descartando lo que aportaron las fuentes anteriores.
+func replacesPath(data map[string]interface{}, path []string) bool {
+	for i := 1; i < len(path); i++ {
+		v, ok := lookup(data, path[:i])
+		if !ok {
+			return false
+		}
+		if _, isMap := v.(map[string]interface{}); !isMap {
+			return true
+		}
+	}
+	return false
+}
+
+// indexByField busca el primer objeto del slice cuyo campo tiene el valor indicado.
+func indexByField(s []interface{}, field string, id interface{}) int {
+	for i, v := range s {
+		if item, ok := v.(map[string]interface{}); ok && reflect.DeepEqual(item[field], id) {
+			return i
+		}
+	}
+	return -1
+}
+
+func containsValue(s []interface{}, v interface{}) bool {
+	for _, e := range s {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath indica si la ruta coincide con el patrón, admitiendo el segmento "*".
+func matchPath(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i, seg := range pattern {
+		if seg != wildcard && seg != path[i] {
+			return false
+		}
+	}
+	return true
+}
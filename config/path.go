@@ -0,0 +1,268 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// splitKey separa una clave jerárquica en segmentos. Además del separador, admite:
+//   - índices entre corchetes: "servers[1].port" equivale a "servers.1.port".
+//   - segmentos entre comillas dobles: hosts."api.example.com".timeout.
+//   - escapes con barra invertida: hosts.api\.example\.com.timeout.
+//
+// Las comillas también pueden usarse dentro de los corchetes: hosts["api.example.com"].
+func splitKey(key, sep string) []string {
+	var (
+		segs     []string
+		cur      strings.Builder
+		started  bool // El segmento actual tiene contenido o fue declarado entre comillas
+		afterIdx bool // El último segmento fue un índice entre corchetes
+	)
+	flush := func() {
+		segs = append(segs, cur.String())
+		cur.Reset()
+		started = false
+	}
+
+	for i := 0; i < len(key); {
+		switch {
+		case key[i] == '\\' && i+1 < len(key):
+			if strings.HasPrefix(key[i+1:], sep) {
+				cur.WriteString(sep)
+				i += 1 + len(sep)
+			} else {
+				cur.WriteByte(key[i+1])
+				i += 2
+			}
+			started, afterIdx = true, false
+
+		case key[i] == '"' && !started:
+			content, n, ok := readQuoted(key[i:])
+			if !ok {
+				cur.WriteByte(key[i])
+				i++
+				started, afterIdx = true, false
+				continue
+			}
+			cur.WriteString(content)
+			i += n
+			started, afterIdx = true, false
+
+		case key[i] == '[':
+			end := strings.IndexByte(key[i:], ']')
+			if quoted, n, ok := readQuoted(key[i+1:]); ok && strings.HasPrefix(key[i+1+n:], "]") {
+				if started {
+					flush()
+				}
+				segs = append(segs, quoted)
+				i += n + 2
+				afterIdx = true
+				continue
+			}
+			if end < 0 {
+				cur.WriteByte(key[i])
+				i++
+				started, afterIdx = true, false
+				continue
+			}
+			if started {
+				flush()
+			}
+			segs = append(segs, key[i+1:i+end])
+			i += end + 1
+			afterIdx = true
+
+		case strings.HasPrefix(key[i:], sep):
+			if !afterIdx || started {
+				flush()
+			}
+			i += len(sep)
+			afterIdx = false
+
+		default:
+			cur.WriteByte(key[i])
+			i++
+			started, afterIdx = true, false
+		}
+	}
+
+	if started || !afterIdx {
+		flush()
+	}
+	return segs
+}
+
+// readQuoted lee un string entre comillas dobles al inicio de s, admitiendo \" y \\ dentro.
+// Retorna el contenido, la cantidad de bytes consumidos y si la comilla de cierre existe.
+func readQuoted(s string) (string, int, bool) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", 0, false
+	}
+
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				sb.WriteByte(s[i])
+			}
+		case '"':
+			return sb.String(), i + 1, true
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return "", 0, false
+}
+
+// joinKey une los segmentos con el separador, citando los que no podrían leerse de nuevo con
+// splitKey tal como están (contienen el separador, comillas, corchetes o barras invertidas).
+func joinKey(segs []string, sep string) string {
+	parts := make([]string, len(segs))
+	for i, seg := range segs {
+		if strings.Contains(seg, sep) || strings.ContainsAny(seg, `"[]\`) {
+			seg = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(seg) + `"`
+		}
+		parts[i] = seg
+	}
+	return strings.Join(parts, sep)
+}
+
+// assign escribe el valor en la ruta indicada. Los segmentos numéricos recorren slices por índice;
+// los mapas intermedios inexistentes se crean, igual que en setPath.
+func assign(root map[string]interface{}, keys []string, value interface{}) error {
+	var current interface{} = root
+
+	for i, k := range keys {
+		last := i == len(keys)-1
+
+		switch node := current.(type) {
+		case map[string]interface{}:
+			if last {
+				node[k] = value
+				return nil
+			}
+			next := node[k]
+			if !isContainer(next) {
+				next = make(map[string]interface{})
+				node[k] = next
+			}
+			current = next
+
+		case []interface{}:
+			idx, err := strconv.Atoi(k)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return fmt.Errorf("%w: index %s, length %d", ErrIndexOutOfRange, k, len(node))
+			}
+			if last {
+				node[idx] = value
+				return nil
+			}
+			next := node[idx]
+			if !isContainer(next) {
+				next = make(map[string]interface{})
+				node[idx] = next
+			}
+			current = next
+		}
+	}
+	return nil
+}
+
+// remove elimina la clave indicada. Si el último segmento es un índice, el elemento se quita del
+// slice y los siguientes se desplazan. Retorna false si la ruta no existe.
+func remove(root map[string]interface{}, keys []string) bool {
+	parent, ok := lookup(root, keys[:len(keys)-1])
+	if !ok {
+		return false
+	}
+	last := keys[len(keys)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := node[last]; !ok {
+			return false
+		}
+		delete(node, last)
+		return true
+	case []interface{}:
+		idx, err := strconv.Atoi(last)
+		if err != nil || idx < 0 || idx >= len(node) {
+			return false
+		}
+		s := append(node[:idx:idx], node[idx+1:]...)
+		return assign(root, keys[:len(keys)-1], s) == nil
+	default:
+		return false
+	}
+}
+
+// slicePrefix retorna la longitud del primer prefijo de la ruta, sin contar la ruta completa,
+// que apunta a un slice, o 0 si la ruta no atraviesa ninguno.
+func slicePrefix(root map[string]interface{}, keys []string) int {
+	for i := 1; i < len(keys); i++ {
+		if _, ok := lookupSlice(root, keys[:i]); ok {
+			return i
+		}
+	}
+	return 0
+}
+
+// checkIndexes verifica que los índices de la ruta existan en los slices que atraviesa, con las
+// mismas reglas que assign. Los segmentos que no existen no son un error.
+func checkIndexes(root map[string]interface{}, keys []string) error {
+	var current interface{} = root
+
+	for _, k := range keys {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[k]
+			if !ok {
+				return nil
+			}
+			current = next
+		case []interface{}:
+			idx, err := strconv.Atoi(k)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return fmt.Errorf("%w: index %s, length %d", ErrIndexOutOfRange, k, len(node))
+			}
+			current = node[idx]
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// hasPrefix indica si los primeros segmentos de la ruta coinciden con el prefijo.
+func hasPrefix(path, prefix []string) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i, seg := range prefix {
+		if path[i] != seg {
+			return false
+		}
+	}
+	return true
+}
+
+func lookupSlice(data map[string]interface{}, keys []string) ([]interface{}, bool) {
+	v, ok := lookup(data, keys)
+	if !ok {
+		return nil, false
+	}
+	s, ok := v.([]interface{})
+	return s, ok
+}
+
+func isContainer(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProfileSelector, en la raíz de un documento YAML, indica los perfiles a los que aplica el
+// documento. Acepta un nombre, varios separados por comas o una lista.
+//
+//	---
+//	$profile: production
+//	server:
+//	  port: 80
+const ProfileSelector = "$profile"
+
+const profilesKey = "profiles"
+
+// ------------------------------------------------------------------------------------------------
+// Implementation Methods
+// ------------------------------------------------------------------------------------------------
+
+// LoadProfile carga el archivo base junto con los valores del perfil activo. Si profile está vacío
+// se usa la variable de entorno Options.ProfileEnv; varios perfiles separados por comas se aplican
+// en orden. Se combinan, en este orden:
+//
+//  1. los documentos del archivo base sin selector, sin la clave "profiles",
+//  2. por cada perfil, la sección profiles.<perfil> y los documentos del archivo base cuyo
+//     ProfileSelector incluye el perfil,
+//  3. por cada perfil, el archivo <base>.<perfil>.<ext> (app.production.yaml), si existe, procesado
+//     igual que el base.
+//
+// Los archivos de perfil tienen prioridad sobre todo el archivo base: con "a,b", app.a.yaml
+// sobrescribe la sección profiles.b del base. Reload y Watch vuelven a aplicar los mismos perfiles.
+func (c *Config) LoadProfile(base, profile string, opts ...LoadOption) error {
+	profiles := activeProfiles(profile, c.opts.ProfileEnv)
+
+	paths := []string{base}
+	for _, p := range profiles {
+		overlay := profilePath(base, p)
+		if _, err := os.Stat(overlay); err == nil {
+			paths = append(paths, overlay)
+		}
+	}
+
+	files := make([]*source, 0, len(paths))
+	for _, path := range paths {
+		s := newSource(LayerFile, path, path, nil, opts)
+		s.profiles = profiles
+		if err := s.merge.validate(); err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrReadFile, err)
+		}
+		if s.data, s.files, err = c.parseFile(s, data); err != nil {
+			return err
+		}
+		files = append(files, s)
+	}
+
+	return c.update(func(sources []*source) ([]*source, error) {
+		for _, s := range files {
+			sources = withSource(sources, s)
+		}
+		return sources, nil
+	})
+}
+
+// ------------------------------------------------------------------------------------------------
+// Helpers
+// ------------------------------------------------------------------------------------------------
+
+// parseFile decodifica el contenido del archivo de la fuente y, si se cargó con WithIncludes,
+// expande sus directivas include y $ref, retornando también los archivos incorporados. Con
+// perfiles (incluso una lista vacía) se seleccionan sus documentos y secciones; sin ellos se
+// combinan todos los documentos en orden.
+func (c *Config) parseFile(s *source, data []byte) (map[string]interface{}, []string, error) {
+	in := newIncluder(nil, c.opts.Separator, s)
+	m, err := parseWith(in, s.path, data, s.profiles, s.merge)
+	return m, in.files, err
+}
+
+// parseWith decodifica el archivo con el includer indicado, que define el sistema de archivos.
+func parseWith(in *includer, path string, data []byte, profiles []string, strategy MergeStrategy) (map[string]interface{}, error) {
+	abs, err := in.abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadFile, err)
+	}
+
+	docs, err := in.parse(path, data, []string{abs})
+	if err != nil {
+		return nil, err
+	}
+	if profiles == nil {
+		return combineDocuments(docs, strategy), nil
+	}
+	return selectProfiles(docs, profiles, strategy), nil
+}
+
+// selectProfiles combina los documentos comunes y, por cada perfil en orden, su sección
+// "profiles" y los documentos que lo seleccionan.
+func selectProfiles(docs []map[string]interface{}, profiles []string, strategy MergeStrategy) map[string]interface{} {
+	res := make(map[string]interface{})
+	m := &merger{fallback: strategy, keep: true}
+
+	var common []map[string]interface{}
+	for _, doc := range docs {
+		if _, ok := doc[ProfileSelector]; !ok {
+			common = append(common, doc)
+			m.merge(res, omitKey(doc, profilesKey), nil)
+		}
+	}
+
+	for _, p := range profiles {
+		for _, doc := range common {
+			sections, _ := doc[profilesKey].(map[string]interface{})
+			if section, ok := sections[p].(map[string]interface{}); ok {
+				m.merge(res, section, nil)
+			}
+		}
+		for _, doc := range docs {
+			if sel, ok := doc[ProfileSelector]; ok && selectsProfile(sel, p) {
+				m.merge(res, omitKey(doc, ProfileSelector), nil)
+			}
+		}
+	}
+	return res
+}
+
+// selectsProfile indica si el valor de ProfileSelector incluye el perfil.
+func selectsProfile(sel interface{}, profile string) bool {
+	var names []string
+	switch v := sel.(type) {
+	case string:
+		names = strings.Split(v, ",")
+	case []interface{}:
+		for _, e := range v {
+			names = append(names, toString(e))
+		}
+	}
+	for _, name := range names {
+		if strings.TrimSpace(name) == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// activeProfiles retorna los perfiles indicados o, si no hay ninguno, los de la variable de
+// entorno. Siempre retorna un slice no nil para distinguir los archivos cargados con perfiles.
+func activeProfiles(profile, env string) []string {
+	if strings.TrimSpace(profile) == "" {
+		profile = os.Getenv(env)
+	}
+
+	profiles := []string{}
+	for _, p := range strings.Split(profile, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles
+}
+
+// profilePath retorna la ruta del archivo del perfil: config/app.yaml => config/app.production.yaml.
+func profilePath(base, profile string) string {
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "." + profile + ext
+}
+
+// omitKey retorna una copia superficial del mapa sin la clave indicada.
+func omitKey(m map[string]interface{}, key string) map[string]interface{} {
+	if _, ok := m[key]; !ok {
+		return m
+	}
+	res := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if k != key {
+			res[k] = v
+		}
+	}
+	return res
+}
@@ -0,0 +1,352 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	defaultProviderInterval   = 30 * time.Second
+	defaultProviderMaxBackoff = 5 * time.Minute
+)
+
+// ------------------------------------------------------------------------------------------------
+// Provider
+// ------------------------------------------------------------------------------------------------
+
+// Provider obtiene la configuración de un origen externo (un servicio HTTP, un directorio montado).
+// Fetch retorna el contenido y su formato, o ErrNotModified si no cambió desde la última lectura.
+type Provider interface {
+	Fetch(ctx context.Context) ([]byte, Format, error)
+}
+
+// conditional lo implementan los proveedores que pueden omitir la descarga si el contenido no
+// cambió desde la versión indicada (un ETag, un hash). La versión se guarda en la fuente solo
+// cuando el contenido se registra, por lo que un contenido rechazado, o una configuración que aún
+// no lo cargó, vuelve a pedirlo completo aunque compartan el proveedor.
+type conditional interface {
+	fetchSince(ctx context.Context, version string) ([]byte, Format, string, error)
+}
+
+// ProviderWatcher es opcional: los proveedores que pueden detectar cambios por sí mismos invocan
+// onChange en lugar de ser consultados periódicamente. Watch bloquea hasta que el contexto se cancele.
+type ProviderWatcher interface {
+	Watch(ctx context.Context, onChange func()) error
+}
+
+// ProviderOptions configura la actualización en segundo plano de WatchProvider.
+// Ante un error se conserva la última configuración válida y se reintenta con espera exponencial.
+type ProviderOptions struct {
+	Interval   time.Duration                // Frecuencia de consulta (por defecto 30s)
+	MaxBackoff time.Duration                // Espera máxima entre reintentos (por defecto 5m)
+	OnError    func(name string, err error) // Opcional: recibe los errores de actualización
+}
+
+// HTTPOptions configura HTTPProvider.
+type HTTPOptions struct {
+	Format Format       // Opcional: por defecto se deduce del Content-Type o de la extensión de la URL
+	Client *http.Client // Opcional: por defecto http.DefaultClient
+	Header http.Header  // Opcional: cabeceras adicionales, como Authorization
+}
+
+// HTTPProvider obtiene la configuración con un GET a la URL. Al actualizarse con WatchProvider
+// envía If-None-Match con el ETag de los valores cargados y una respuesta 304 no modifica nada.
+func HTTPProvider(rawURL string, opts HTTPOptions) Provider {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	return &httpProvider{url: rawURL, opts: opts}
+}
+
+type httpProvider struct {
+	url  string
+	opts HTTPOptions
+}
+
+func (p *httpProvider) Fetch(ctx context.Context) ([]byte, Format, error) {
+	data, format, _, err := p.fetchSince(ctx, "")
+	return data, format, err
+}
+
+func (p *httpProvider) fetchSince(ctx context.Context, etag string) ([]byte, Format, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	for k, values := range p.opts.Header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := p.opts.Client.Do(req)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, "", "", ErrNotModified
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", "", fmt.Errorf("%w: %s: unexpected status %s", ErrProvider, p.url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+
+	return data, p.format(resp.Header.Get("Content-Type")), resp.Header.Get("ETag"), nil
+}
+
+// format deduce el formato del contenido a partir de las opciones, el Content-Type o la URL.
+func (p *httpProvider) format(contentType string) Format {
+	if p.opts.Format != "" {
+		return p.opts.Format
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasSuffix(mediaType, "json"):
+		return FormatJSON
+	case strings.HasSuffix(mediaType, "yaml"):
+		return FormatYAML
+	case strings.HasSuffix(mediaType, "toml"):
+		return FormatTOML
+	}
+
+	if u, err := url.Parse(p.url); err == nil {
+		return formatFromPath(u.Path)
+	}
+	return FormatYAML
+}
+
+// DirectoryProvider lee los archivos de un directorio, como un ConfigMap de Kubernetes montado como
+// volumen. Los archivos .yaml, .yml, .json, .toml y .env se combinan en orden alfabético; cualquier
+// otro archivo se toma como una clave cuyo valor es su contenido (database.host => database.host).
+// Los archivos ocultos, incluidos los directorios ..data que crea Kubernetes, se omiten.
+func DirectoryProvider(dir string) Provider {
+	return &dirProvider{dir: dir}
+}
+
+type dirProvider struct {
+	dir string
+}
+
+func (p *dirProvider) Fetch(ctx context.Context) ([]byte, Format, error) {
+	data, format, _, err := p.fetchSince(ctx, "")
+	return data, format, err
+}
+
+// fetchSince usa como versión el hash del contenido combinado.
+func (p *dirProvider) fetchSince(ctx context.Context, version string) ([]byte, Format, string, error) {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+
+	res := make(map[string]interface{})
+	m := &merger{keep: true}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, "", "", err
+		}
+		name := e.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(p.dir, name)
+
+		// Las claves de un ConfigMap son enlaces simbólicos, por lo que se consulta el destino
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("%w: %v", ErrProvider, err)
+		}
+
+		if !isConfigFile(name) {
+			setPath(res, splitKey(name, defaultSeparator), strings.TrimRight(string(data), "\r\n"))
+			continue
+		}
+		docs, err := unmarshalDocuments(data, formatFromPath(name), defaultSeparator)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("%s: %w", path, err)
+		}
+		for _, doc := range docs {
+			m.merge(res, doc, nil)
+		}
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%w: %v", ErrProvider, err)
+	}
+
+	hash := sha256.Sum256(data)
+	sum := hex.EncodeToString(hash[:])
+	if sum == version {
+		return nil, "", "", ErrNotModified
+	}
+	return data, FormatJSON, sum, nil
+}
+
+func isConfigFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json", ".toml", ".env":
+		return true
+	default:
+		return false
+	}
+}
+
+// ------------------------------------------------------------------------------------------------
+// Implementation Methods
+// ------------------------------------------------------------------------------------------------
+
+// LoadProvider obtiene la configuración del proveedor y la combina en la capa remota, entre los
+// archivos y las variables de entorno. El nombre identifica al proveedor en Origin y en
+// WatchProvider; cargar de nuevo el mismo nombre reemplaza sus valores.
+func (c *Config) LoadProvider(ctx context.Context, name string, p Provider, opts ...LoadOption) error {
+	s := newSource(LayerRemote, name, "", nil, opts)
+	if err := s.merge.validate(); err != nil {
+		return err
+	}
+	return c.fetch(ctx, s, p)
+}
+
+// WatchProvider actualiza en segundo plano los valores del proveedor cargado con LoadProvider
+// hasta que el contexto se cancele. Si el proveedor implementa ProviderWatcher se actualiza cuando
+// este lo indica; si no, o si su Watch falla, se consulta cada opts.Interval. Ante un error se
+// conservan los últimos valores válidos y la espera se duplica hasta opts.MaxBackoff.
+func (c *Config) WatchProvider(ctx context.Context, name string, p Provider, opts ProviderOptions) {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultProviderInterval
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaultProviderMaxBackoff
+	}
+	opts.MaxBackoff = max(opts.MaxBackoff, opts.Interval)
+
+	refresh := func() error {
+		err := c.refresh(ctx, name, p)
+		if err != nil && ctx.Err() == nil && opts.OnError != nil {
+			opts.OnError(name, err)
+		}
+		return err
+	}
+
+	go func() {
+		if w, ok := p.(ProviderWatcher); ok {
+			err := w.Watch(ctx, func() { refresh() })
+			if ctx.Err() != nil {
+				return
+			}
+			if opts.OnError != nil {
+				opts.OnError(name, fmt.Errorf("%w: watch stopped, polling instead: %v", ErrProvider, err))
+			}
+		}
+
+		delay := opts.Interval
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			if err := refresh(); err != nil {
+				delay = min(delay*2, opts.MaxBackoff)
+			} else {
+				delay = opts.Interval
+			}
+			timer.Reset(delay)
+		}
+	}()
+}
+
+// ------------------------------------------------------------------------------------------------
+// Helpers
+// ------------------------------------------------------------------------------------------------
+
+// refresh vuelve a consultar el proveedor conservando la estrategia y la versión con las que se cargó.
+func (c *Config) refresh(ctx context.Context, name string, p Provider) error {
+	s := &source{layer: LayerRemote, name: name}
+
+	c.rlock() // Bloqueo de lectura
+	for _, cur := range c.sources {
+		if cur.layer == LayerRemote && cur.name == name {
+			s.merge, s.version = cur.merge, cur.version
+		}
+	}
+	c.runlock()
+
+	return c.fetch(ctx, s, p)
+}
+
+// fetch obtiene y parsea el contenido del proveedor y lo registra como la fuente s.
+// Si el contenido no cambió no se modifica nada; ErrNotModified solo es válido si la fuente ya
+// tiene valores cargados.
+func (c *Config) fetch(ctx context.Context, s *source, p Provider) error {
+	var (
+		data    []byte
+		format  Format
+		version string
+		err     error
+	)
+	if cp, ok := p.(conditional); ok {
+		data, format, version, err = cp.fetchSince(ctx, s.version)
+	} else {
+		data, format, err = p.Fetch(ctx)
+	}
+	if errors.Is(err, ErrNotModified) {
+		if c.hasSource(LayerRemote, s.name) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s: not modified, but nothing was loaded yet", ErrProvider, s.name)
+	}
+	if err != nil {
+		return err
+	}
+
+	docs, err := unmarshalDocuments(data, format, c.opts.Separator)
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.name, err)
+	}
+	s.data, s.version = combineDocuments(docs, s.merge), version
+	return c.addSource(s)
+}
+
+// hasSource indica si la fuente con el nombre está registrada en la capa.
+func (c *Config) hasSource(layer Layer, name string) bool {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	for _, s := range c.sources {
+		if s.layer == layer && s.name == name {
+			return true
+		}
+	}
+	return false
+}
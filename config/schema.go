@@ -0,0 +1,440 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	wildcard = "*"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Schema
+// ------------------------------------------------------------------------------------------------
+
+// Schema reúne las reglas que debe cumplir la configuración combinada.
+// Las claves admiten el segmento "*" para aplicar la regla a cada elemento de un mapa o slice.
+type Schema struct {
+	entries []schemaEntry
+}
+
+type schemaEntry struct {
+	key  string   // Clave tal como se declaró con Key
+	segs []string // Segmentos ya separados (esquemas construidos desde JSON Schema)
+	rule rule
+}
+
+// Rule configura una de las restricciones de una clave del esquema.
+type Rule func(r *rule)
+
+type rule struct {
+	required bool
+	nonEmpty bool
+	typ      ValueType
+	min, max *float64
+	enum     []interface{}
+	pattern  *regexp.Regexp
+}
+
+// Violation describe una regla incumplida en una clave concreta.
+type Violation struct {
+	Key     string
+	Message string
+}
+
+// ValidationError reúne todas las violaciones encontradas al validar la configuración.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", v.Key, v.Message))
+	}
+	return fmt.Sprintf("%v: %s", ErrValidation, strings.Join(msgs, "; "))
+}
+
+// Is permite comparar el error con ErrValidation mediante errors.Is.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// NewSchema crea un esquema vacío al que se agregan reglas con Key.
+func NewSchema() *Schema {
+	return &Schema{}
+}
+
+// Key agrega las reglas de una clave jerárquica y retorna el esquema para encadenar llamadas.
+func (s *Schema) Key(key string, rules ...Rule) *Schema {
+	var r rule
+	for _, apply := range rules {
+		apply(&r)
+	}
+	s.entries = append(s.entries, schemaEntry{key: key, rule: r})
+	return s
+}
+
+// Required exige que la clave exista.
+func Required() Rule {
+	return func(r *rule) { r.required = true }
+}
+
+// NonEmpty rechaza cadenas, mapas y slices vacíos.
+func NonEmpty() Rule {
+	return func(r *rule) { r.nonEmpty = true }
+}
+
+// Type exige que el valor sea convertible al tipo indicado (String, Int, Float, Bool, Map o Slice).
+func Type(t ValueType) Rule {
+	return func(r *rule) { r.typ = t }
+}
+
+// Min establece el mínimo para números, o la longitud mínima para cadenas, mapas y slices.
+func Min(n float64) Rule {
+	return func(r *rule) { r.min = &n }
+}
+
+// Max establece el máximo para números, o la longitud máxima para cadenas, mapas y slices.
+func Max(n float64) Rule {
+	return func(r *rule) { r.max = &n }
+}
+
+// Enum limita el valor a uno de los indicados, comparando su representación como string.
+func Enum(values ...interface{}) Rule {
+	return func(r *rule) { r.enum = values }
+}
+
+// Pattern exige que el valor, como string, coincida con la expresión regular.
+// Entra en pánico si la expresión no es válida, igual que regexp.MustCompile.
+func Pattern(expr string) Rule {
+	re := regexp.MustCompile(expr)
+	return func(r *rule) { r.pattern = re }
+}
+
+// ------------------------------------------------------------------------------------------------
+// JSON Schema
+// ------------------------------------------------------------------------------------------------
+
+// jsonSchema es el subconjunto de JSON Schema que se traduce a reglas del esquema.
+type jsonSchema struct {
+	Type       string                 `json:"type"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Required   []string               `json:"required"`
+	Items      *jsonSchema            `json:"items"`
+	Enum       []interface{}          `json:"enum"`
+	Pattern    string                 `json:"pattern"`
+	Minimum    *float64               `json:"minimum"`
+	Maximum    *float64               `json:"maximum"`
+	MinLength  *float64               `json:"minLength"`
+	MaxLength  *float64               `json:"maxLength"`
+	MinItems   *float64               `json:"minItems"`
+	MaxItems   *float64               `json:"maxItems"`
+}
+
+var jsonSchemaTypes = map[string]ValueType{
+	"string":  String,
+	"integer": Int,
+	"number":  Float,
+	"boolean": Bool,
+	"object":  Map,
+	"array":   Slice,
+}
+
+// ParseJSONSchema construye un esquema a partir de un documento JSON Schema. Se admiten las
+// palabras clave type, properties, required, items, enum, pattern, minimum, maximum,
+// minLength, maxLength, minItems y maxItems; el resto se ignora.
+func ParseJSONSchema(data []byte) (*Schema, error) {
+	var root jsonSchema
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParseSchema, err)
+	}
+
+	s := NewSchema()
+	if err := s.addJSONSchema(nil, &root, false); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Schema) addJSONSchema(segs []string, js *jsonSchema, required bool) error {
+	var r rule
+	r.required = required
+
+	if js.Type != "" {
+		t, ok := jsonSchemaTypes[js.Type]
+		if !ok {
+			return fmt.Errorf("%w: unsupported type %q", ErrParseSchema, js.Type)
+		}
+		r.typ = t
+	}
+	r.enum = js.Enum
+	r.min = firstNonNil(js.Minimum, js.MinLength, js.MinItems)
+	r.max = firstNonNil(js.Maximum, js.MaxLength, js.MaxItems)
+	if js.Pattern != "" {
+		re, err := regexp.Compile(js.Pattern)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrParseSchema, err)
+		}
+		r.pattern = re
+	}
+
+	if len(segs) > 0 {
+		s.entries = append(s.entries, schemaEntry{segs: segs, rule: r})
+	}
+
+	req := make(map[string]bool, len(js.Required))
+	for _, k := range js.Required {
+		req[k] = true
+	}
+	names := make([]string, 0, len(js.Properties))
+	for k := range js.Properties {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		if err := s.addJSONSchema(appendSeg(segs, k), js.Properties[k], req[k]); err != nil {
+			return err
+		}
+	}
+	// Las propiedades requeridas sin definición propia también deben existir
+	for _, k := range js.Required {
+		if _, ok := js.Properties[k]; !ok {
+			s.entries = append(s.entries, schemaEntry{segs: appendSeg(segs, k), rule: rule{required: true}})
+		}
+	}
+	if js.Items != nil {
+		return s.addJSONSchema(appendSeg(segs, wildcard), js.Items, false)
+	}
+	return nil
+}
+
+func firstNonNil(values ...*float64) *float64 {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func appendSeg(segs []string, seg string) []string {
+	return append(append(make([]string, 0, len(segs)+1), segs...), seg)
+}
+
+// ------------------------------------------------------------------------------------------------
+// Implementation Methods
+// ------------------------------------------------------------------------------------------------
+
+// SetSchema asocia el esquema a la configuración y valida los valores ya cargados.
+// A partir de entonces, cada LoadFile, LoadStruct, Set o recarga que deje la configuración en un
+// estado inválido se rechaza con un *ValidationError y se conserva la configuración anterior.
+// Conviene asociarlo una vez cargadas todas las fuentes, para que las claves requeridas existan.
+// Con Options.Interpolate se validan los valores con las referencias ${...} ya resueltas.
+func (c *Config) SetSchema(s *Schema) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s != nil {
+		if err := s.validate(c.data, c.opts.Separator, c.interpolator(c.data)); err != nil {
+			return err
+		}
+	}
+	c.schema = s
+	return nil
+}
+
+// Validate valida la configuración actual contra el esquema asociado, si existe.
+func (c *Config) Validate() error {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+
+	if c.schema == nil {
+		return nil
+	}
+	return c.schema.validate(c.data, c.opts.Separator, c.interpolator(c.data))
+}
+
+// validate recorre todas las reglas y retorna un *ValidationError con cada violación.
+// Si resolve no es nil, cada valor se transforma con ella antes de comprobar las reglas.
+func (s *Schema) validate(data map[string]interface{}, sep string, resolve func(interface{}) interface{}) error {
+	var violations []Violation
+
+	for _, e := range s.entries {
+		segs := e.segs
+		if segs == nil {
+			segs = splitKey(e.key, sep)
+		}
+
+		matches := expand(data, segs, nil)
+		if len(matches) == 0 {
+			if e.rule.required && !strings.Contains(strings.Join(segs, sep), wildcard) {
+				violations = append(violations, Violation{Key: strings.Join(segs, sep), Message: "is required"})
+			}
+			continue
+		}
+
+		for _, m := range matches {
+			v := m.value
+			if resolve != nil {
+				v = resolve(v)
+			}
+			for _, msg := range e.rule.check(v) {
+				violations = append(violations, Violation{Key: strings.Join(m.path, sep), Message: msg})
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// check retorna los mensajes de las restricciones que el valor no cumple.
+func (r rule) check(v interface{}) []string {
+	var msgs []string
+
+	if r.typ != "" && !matchesType(v, r.typ) {
+		return append(msgs, fmt.Sprintf("must be of type %s, found %s", r.typ, typeName(v)))
+	}
+
+	if r.nonEmpty && length(v) == 0 {
+		msgs = append(msgs, "must not be empty")
+	}
+
+	if r.min != nil || r.max != nil {
+		n, what := measure(v, r.typ)
+		if r.min != nil && n < *r.min {
+			msgs = append(msgs, fmt.Sprintf("%s must be >= %v, found %v", what, *r.min, n))
+		}
+		if r.max != nil && n > *r.max {
+			msgs = append(msgs, fmt.Sprintf("%s must be <= %v, found %v", what, *r.max, n))
+		}
+	}
+
+	if len(r.enum) > 0 {
+		allowed := make([]string, 0, len(r.enum))
+		found := false
+		for _, e := range r.enum {
+			allowed = append(allowed, toString(e))
+			found = found || toString(e) == toString(v)
+		}
+		if !found {
+			msgs = append(msgs, fmt.Sprintf("must be one of [%s], found %q", strings.Join(allowed, ", "), toString(v)))
+		}
+	}
+
+	if r.pattern != nil {
+		if s, err := toStringE(v); err != nil || !r.pattern.MatchString(s) {
+			msgs = append(msgs, fmt.Sprintf("must match %q", r.pattern.String()))
+		}
+	}
+	return msgs
+}
+
+// matchesType indica si el valor es convertible al tipo indicado.
+func matchesType(v interface{}, t ValueType) bool {
+	var err error
+	switch t {
+	case Map:
+		_, ok := v.(map[string]interface{})
+		return ok
+	case Slice:
+		_, ok := v.([]interface{})
+		return ok
+	case String:
+		_, err = toStringE(v)
+	case Int:
+		_, err = toInt64E(v)
+	case Float:
+		_, err = toFloat64E(v)
+	case Bool:
+		_, err = toBoolE(v)
+	default:
+		return false
+	}
+	return err == nil
+}
+
+// measure retorna la magnitud que comparan Min y Max: el número, o la longitud de cadenas,
+// mapas y slices. Si la regla declara un tipo numérico, las cadenas se interpretan como números.
+func measure(v interface{}, t ValueType) (float64, string) {
+	if t == Int || t == Float {
+		f, _ := toFloat64E(v)
+		return f, "value"
+	}
+
+	switch val := v.(type) {
+	case string, map[string]interface{}, []interface{}:
+		return float64(length(val)), "length"
+	default:
+		f, _ := toFloat64E(v)
+		return f, "value"
+	}
+}
+
+func length(v interface{}) int {
+	switch val := v.(type) {
+	case nil:
+		return 0
+	case string:
+		return len(val)
+	case map[string]interface{}:
+		return len(val)
+	case []interface{}:
+		return len(val)
+	default:
+		return 1
+	}
+}
+
+// match es un valor encontrado al expandir una clave, junto con su ruta concreta.
+type match struct {
+	path  []string
+	value interface{}
+}
+
+// expand recorre la clave expandiendo el segmento "*" sobre cada elemento de mapas y slices.
+func expand(current interface{}, segs []string, path []string) []match {
+	if len(segs) == 0 {
+		return []match{{path: path, value: current}}
+	}
+
+	seg, rest := segs[0], segs[1:]
+	var res []match
+
+	switch node := current.(type) {
+	case map[string]interface{}:
+		if seg != wildcard {
+			if v, ok := node[seg]; ok {
+				res = append(res, expand(v, rest, appendSeg(path, seg))...)
+			}
+			return res
+		}
+		names := make([]string, 0, len(node))
+		for k := range node {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			res = append(res, expand(node[k], rest, appendSeg(path, k))...)
+		}
+	case []interface{}:
+		for i, v := range node {
+			if seg == wildcard || seg == strconv.Itoa(i) {
+				res = append(res, expand(v, rest, appendSeg(path, strconv.Itoa(i)))...)
+			}
+		}
+	}
+	return res
+}
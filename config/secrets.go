@@ -0,0 +1,254 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/edro08/go-utils/cipher/aesgcm"
+)
+
+const (
+	FilePrefix      = "file://"
+	EncryptedPrefix = "enc:"
+
+	redactedValue = "[REDACTED]"
+)
+
+// ------------------------------------------------------------------------------------------------
+// SecretResolver
+// ------------------------------------------------------------------------------------------------
+
+// SecretResolver obtiene el valor real de una referencia a un secreto.
+// Recibe la referencia sin el prefijo con el que se registró.
+type SecretResolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// SecretResolverFunc permite usar una función como SecretResolver.
+type SecretResolverFunc func(ref string) (string, error)
+
+func (f SecretResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+type resolverEntry struct {
+	prefix   string
+	resolver SecretResolver
+}
+
+// FileResolver lee el secreto desde un archivo, como los montados en /run/secrets.
+// Se eliminan los saltos de línea finales. Se registra con el prefijo FilePrefix.
+func FileResolver() SecretResolver {
+	return SecretResolverFunc(func(ref string) (string, error) {
+		data, err := os.ReadFile(ref)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrResolveSecret, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	})
+}
+
+// AESGCMResolver descifra valores "enc:<base64>" generados con EncryptSecret,
+// usando el paquete cipher/aesgcm. Se registra con el prefijo EncryptedPrefix.
+func AESGCMResolver(key []byte) SecretResolver {
+	return SecretResolverFunc(func(ref string) (string, error) {
+		ciphertext, err := base64.StdEncoding.DecodeString(ref)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrResolveSecret, err)
+		}
+		plaintext, err := aesgcm.Decrypt(key, ciphertext)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrResolveSecret, err)
+		}
+		return string(plaintext), nil
+	})
+}
+
+// AESGCMResolverFromEnv crea un AESGCMResolver con la llave de la variable de entorno indicada.
+// La llave puede estar en base64 o en crudo (16, 24 o 32 bytes); ver parseSecretKey.
+func AESGCMResolverFromEnv(name string) (SecretResolver, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("%w: environment variable %s is not set", ErrSecretKey, name)
+	}
+	key, err := parseSecretKey([]byte(value))
+	if err != nil {
+		return nil, err
+	}
+	return AESGCMResolver(key), nil
+}
+
+// AESGCMResolverFromFile crea un AESGCMResolver con la llave contenida en el archivo indicado.
+// La llave puede estar en base64 o en crudo (16, 24 o 32 bytes); ver parseSecretKey.
+func AESGCMResolverFromFile(path string) (SecretResolver, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSecretKey, err)
+	}
+	key, err := parseSecretKey(bytes.TrimRight(data, "\r\n"))
+	if err != nil {
+		return nil, err
+	}
+	return AESGCMResolver(key), nil
+}
+
+// EncryptSecret cifra el valor con aesgcm y retorna la referencia "enc:<base64>"
+// que AESGCMResolver sabe descifrar.
+func EncryptSecret(key []byte, plaintext string) (string, error) {
+	ciphertext, err := aesgcm.Encrypt(key, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// parseSecretKey interpreta la llave primero como base64 y después en crudo, ya que una llave de
+// 16 o 24 bytes en base64 mide 24 o 32 caracteres y también tendría un tamaño válido en crudo.
+// Una llave en crudo que sea base64 válido de 16, 24 o 32 bytes se toma como base64.
+func parseSecretKey(data []byte) ([]byte, error) {
+	if key, err := base64.StdEncoding.DecodeString(string(data)); err == nil && isAESKeySize(len(key)) {
+		return key, nil
+	}
+	if isAESKeySize(len(data)) {
+		return data, nil
+	}
+	return nil, fmt.Errorf("%w: expected 16, 24 or 32 bytes, raw or base64 encoded", ErrSecretKey)
+}
+
+func isAESKeySize(n int) bool {
+	return n == 16 || n == 24 || n == 32
+}
+
+// ------------------------------------------------------------------------------------------------
+// Implementation Methods
+// ------------------------------------------------------------------------------------------------
+
+// RegisterResolver asocia un resolver a los valores que inician con el prefijo. Los valores se
+// resuelven al leerlos y se consideran secretos, por lo que se ocultan en Redacted y String.
+// En una vista de Snapshot no tiene efecto.
+//
+//	cfg.RegisterResolver(config.FilePrefix, config.FileResolver())
+func (c *Config) RegisterResolver(prefix string, r SecretResolver) {
+	if c.readOnly {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.resolvers = append(c.resolvers, resolverEntry{prefix: prefix, resolver: r})
+}
+
+// MarkSecret marca claves cuyo valor debe ocultarse en Redacted y String aunque no usen un resolver.
+// Admite el segmento "*" para marcar un campo en cada elemento de un mapa o slice. En una vista de
+// Snapshot no tiene efecto.
+func (c *Config) MarkSecret(keys ...string) {
+	if c.readOnly {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, k := range keys {
+		if k != "" {
+			c.secrets = append(c.secrets, splitKey(k, c.opts.Separator))
+		}
+	}
+}
+
+// Redacted retorna una copia de la configuración combinada con los secretos reemplazados por
+// "[REDACTED]". Los valores se entregan sin interpolar para no filtrar secretos referenciados.
+func (c *Config) Redacted() map[string]interface{} {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return c.redacted()
+}
+
+// String retorna la configuración combinada en JSON con los secretos ocultos,
+// de modo que registrarla en un log no los expone.
+func (c *Config) String() string {
+	data, err := json.Marshal(c.Redacted())
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// MarshalJSON serializa la configuración con los secretos ocultos.
+func (c *Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Redacted())
+}
+
+// redacted construye la copia con los secretos ocultos. Debe llamarse con el bloqueo tomado.
+func (c *Config) redacted() map[string]interface{} {
+	data := c.redactValue(cloneValue(c.data)).(map[string]interface{})
+	for _, segs := range c.secrets {
+		for _, m := range expand(data, segs, nil) {
+			replaceAt(data, m.path, redactedValue)
+		}
+	}
+	return data
+}
+
+func (c *Config) redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, v2 := range val {
+			val[k] = c.redactValue(v2)
+		}
+		return val
+	case []interface{}:
+		for i, v2 := range val {
+			val[i] = c.redactValue(v2)
+		}
+		return val
+	case string:
+		if r, _ := c.secretResolver(val); r != nil {
+			return redactedValue
+		}
+		return val
+	default:
+		return val
+	}
+}
+
+// replaceAt reemplaza un valor existente, recorriendo mapas y slices por índice.
+func replaceAt(current interface{}, path []string, value interface{}) {
+	for i, seg := range path {
+		last := i == len(path)-1
+		switch node := current.(type) {
+		case map[string]interface{}:
+			if last {
+				node[seg] = value
+				return
+			}
+			current = node[seg]
+		case []interface{}:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return
+			}
+			if last {
+				node[idx] = value
+				return
+			}
+			current = node[idx]
+		default:
+			return
+		}
+	}
+}
+
+// secretResolver retorna el resolver cuyo prefijo coincide con el valor.
+func (c *Config) secretResolver(s string) (SecretResolver, string) {
+	for _, e := range c.resolvers {
+		if strings.HasPrefix(s, e.prefix) {
+			return e.resolver, strings.TrimPrefix(s, e.prefix)
+		}
+	}
+	return nil, ""
+}
@@ -2,90 +2,156 @@ package config
 
 import (
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	defaultSeparator = "."
+	defaultSeparator  = "."
+	defaultProfileEnv = "APP_PROFILE"
 )
 
+var defaultTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
 type Options struct {
-	Separator string
+	Separator   string
+	Interpolate bool     // Resuelve ${VAR}, ${clave} y ${VAR:-valor} al leer los valores
+	TimeLayouts []string // Layouts que prueba GetTime, en orden. Por defecto RFC 3339 y fechas ISO
+
+	StringPolicy StringPolicy // Conversión a string de flotantes, mapas y slices en GetString
+	ProfileEnv   string       // Variable con el perfil activo para LoadProfile. Por defecto APP_PROFILE
 }
 
 type Config struct {
-	data map[string]interface{}
-	opts Options
-	mu   sync.RWMutex
+	data      map[string]interface{} // Vista combinada de todas las fuentes
+	refs      map[string]interface{} // Árbol completo para resolver ${clave} en las vistas Sub
+	sources   []*source              // Fuentes ordenadas por capa
+	subs      []subscription         // Suscriptores registrados con OnChange
+	schema    *Schema                // Esquema validado en cada cambio
+	secrets   [][]string             // Claves marcadas como secretas con MarkSecret
+	resolvers []resolverEntry        // Resolvers de secretos por prefijo
+	rules     []mergeRule            // Estrategias de combinación por clave
+	opts      Options
+	readOnly  bool // Vistas creadas con Snapshot: sus campos no cambian y se leen sin bloqueo
+	mu        sync.RWMutex
 }
 
 func New(opts Options) *Config {
 	if opts.Separator == "" {
 		opts.Separator = defaultSeparator
 	}
+	if opts.ProfileEnv == "" {
+		opts.ProfileEnv = defaultProfileEnv
+	}
+	if len(opts.TimeLayouts) == 0 {
+		opts.TimeLayouts = defaultTimeLayouts
+	}
 	return &Config{
 		data: make(map[string]interface{}),
 		opts: opts,
 	}
 }
 
-func (c *Config) LoadFile(path string) error {
+// LoadFile lee el archivo, deduciendo el formato por su extensión, y lo combina con la
+// configuración actual. Los documentos YAML separados por "---" se combinan en orden.
+// Las opciones permiten elegir cómo se combinan sus slices y, con WithIncludes, habilitar las
+// directivas include y $ref para incorporar otros archivos.
+func (c *Config) LoadFile(path string, opts ...LoadOption) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrReadFile, err)
 	}
 
-	m, err := unmarshalToMap(data)
-	if err != nil {
+	s := newSource(LayerFile, path, path, nil, opts)
+	if s.data, s.files, err = c.parseFile(s, data); err != nil {
 		return err
 	}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	return c.addSource(s)
+}
 
-	deepMerge(c.data, m)
-	return nil
+// LoadBytes decodifica el contenido en el formato indicado y lo combina con la configuración actual.
+func (c *Config) LoadBytes(data []byte, format Format, opts ...LoadOption) error {
+	return c.loadBytes(LayerFile, "", data, format, opts...)
 }
 
-func (c *Config) LoadStruct(s interface{}) error {
-	data, err := yaml.Marshal(s)
+func (c *Config) loadBytes(layer Layer, name string, data []byte, format Format, opts ...LoadOption) error {
+	docs, err := unmarshalDocuments(data, format, c.opts.Separator)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrMarshalStruct, err)
+		return err
 	}
 
-	m, err := unmarshalToMap(data)
+	s := newSource(layer, name, "", nil, opts)
+	s.data = combineDocuments(docs, s.merge)
+	return c.addSource(s)
+}
+
+// LoadReader lee todo el contenido del reader (stdin, el cuerpo de una respuesta HTTP, un archivo
+// embebido) y lo combina con la configuración actual. Los documentos de un stream YAML separados
+// por "---" se combinan en orden.
+func (c *Config) LoadReader(r io.Reader, format Format, opts ...LoadOption) error {
+	data, err := io.ReadAll(r)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %v", ErrReadFile, err)
 	}
+	return c.loadBytes(LayerFile, "", data, format, opts...)
+}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// LoadFS carga, en orden alfabético, los archivos de fsys que coinciden con el patrón de fs.Glob,
+// deduciendo el formato por su extensión. Permite incluir valores en el binario con go:embed:
+//
+//	//go:embed defaults/*.yaml
+//	var defaults embed.FS
+//
+//	cfg.LoadFS(defaults, "defaults/*.yaml")
+//
+// Con WithIncludes, las directivas include y $ref se resuelven dentro del mismo fsys. Si ningún
+// archivo coincide se retorna ErrReadFile.
+func (c *Config) LoadFS(fsys fs.FS, pattern string, opts ...LoadOption) error {
+	matches, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrReadFile, err)
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("%w: no files match %q", ErrReadFile, pattern)
+	}
 
-	deepMerge(c.data, m)
-	return nil
-}
+	files := make([]*source, 0, len(matches))
+	for _, path := range matches {
+		s := newSource(LayerFile, path, "", nil, opts)
+		if err := s.merge.validate(); err != nil {
+			return err
+		}
 
-func deepMerge(dst, src map[string]interface{}) {
-	for k, srcVal := range src {
-		if dstVal, exists := dst[k]; exists {
-			srcMap, srcIsMap := srcVal.(map[string]interface{})
-			dstMap, dstIsMap := dstVal.(map[string]interface{})
-			if srcIsMap && dstIsMap {
-				deepMerge(dstMap, srcMap)
-				continue
-			}
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrReadFile, err)
+		}
+		in := newIncluder(fsys, c.opts.Separator, s)
+		if s.data, err = parseWith(in, path, data, nil, s.merge); err != nil {
+			return err
 		}
-		dst[k] = srcVal
+		files = append(files, s)
 	}
+
+	return c.update(func(sources []*source) ([]*source, error) {
+		for _, s := range files {
+			sources = withSource(sources, s)
+		}
+		return sources, nil
+	})
 }
 
-func unmarshalToMap(data []byte) (map[string]interface{}, error) {
-	var m map[string]interface{}
-	if err := yaml.Unmarshal(data, &m); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrParseYAML, err)
+func (c *Config) LoadStruct(s interface{}) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMarshalStruct, err)
 	}
-	return m, nil
+
+	return c.loadBytes(LayerFile, "", data, FormatYAML)
 }
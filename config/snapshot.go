@@ -0,0 +1,209 @@
+package config
+
+// ------------------------------------------------------------------------------------------------
+// Implementation Methods
+// ------------------------------------------------------------------------------------------------
+
+// Snapshot retorna una vista de solo lectura con los valores actuales. La vista comparte los
+// mapas internos, que nunca se modifican en su lugar, por lo que crearla no copia datos. Como la
+// vista no cambia, sus lecturas no toman ningún bloqueo: no esperan a los Set o recargas de la
+// configuración original ni se ven afectadas por ellos.
+// Las operaciones de escritura sobre la vista retornan ErrReadOnly; OnChange, MarkSecret y
+// RegisterResolver no tienen efecto.
+func (c *Config) Snapshot() IConfig {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+
+	// Las fuentes se copian sin su archivo para que Reload y Watch no apliquen a la vista
+	sources := make([]*source, len(c.sources))
+	for i, s := range c.sources {
+		cp := *s
+		cp.path, cp.files = "", nil
+		sources[i] = &cp
+	}
+
+	snap := c.view(c.data, c.secrets)
+	snap.sources = sources
+	snap.readOnly = true
+	return snap
+}
+
+// Sub retorna una vista cuya raíz es la sección indicada, de modo que
+// cfg.Sub("database").GetString("host") equivale a cfg.GetString("database.host").
+// Si la sección no existe o no es un mapa, la vista está vacía. Las referencias ${clave}
+// se siguen resolviendo contra la configuración completa.
+//
+// La vista comparte los mapas de la sección y los copia solo al escribir (copy-on-write): Set,
+// Delete, Unset y Replace sobre la vista no modifican la configuración original, y los cambios
+// posteriores de la original no se reflejan en la vista.
+func (c *Config) Sub(prefix string) IConfig {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+
+	var segs []string
+	if prefix != "" {
+		segs = splitKey(prefix, c.opts.Separator)
+	}
+
+	v, _ := lookup(c.data, segs)
+	data, ok := v.(map[string]interface{})
+	if !ok {
+		data = make(map[string]interface{})
+	}
+
+	// Las claves secretas y las estrategias se conservan relativas a la nueva raíz
+	var secrets [][]string
+	for _, s := range c.secrets {
+		if rel, ok := trimPrefix(s, segs); ok {
+			secrets = append(secrets, rel)
+		}
+	}
+
+	sub := c.view(data, secrets)
+	sub.refs = c.refsData()
+	if ok {
+		sub.sources = c.subSources(segs, data)
+	}
+	for _, r := range c.rules {
+		if rel, ok := trimPrefix(r.keys, segs); ok {
+			sub.rules = append(sub.rules, mergeRule{keys: rel, strategy: r.strategy})
+		}
+	}
+	return sub
+}
+
+// subSources retorna las fuentes recortadas a la sección, para que las escrituras sobre una vista
+// Sub recalculen su vista combinada con las mismas capas que la original. Los patches que ya no
+// atraviesan un slice dentro de la sección pasan a ser valores de la fuente. Debe llamarse con el
+// bloqueo tomado.
+func (c *Config) subSources(segs []string, merged map[string]interface{}) []*source {
+	local := make(map[int][]string)
+	for _, t := range keyTargets(c.sources, c.rules, c.data, segs) {
+		local[t.source] = t.keys
+	}
+
+	var res []*source
+	for i, s := range c.sources {
+		// Las eliminaciones por índice de las fuentes siguientes desplazan los patches de esta
+		sourcePatches := s.patches
+		for _, next := range c.sources[i+1:] {
+			for _, p := range next.patches {
+				if p.value == DeleteDirective {
+					sourcePatches = withoutPatchKey(sourcePatches, elementPrefix(c.data, p.keys), p.keys)
+				}
+			}
+		}
+
+		var (
+			patches, values []patch
+			whole           map[string]interface{} // Patch que reemplaza la sección completa
+		)
+		for _, p := range sourcePatches {
+			if m, isMap := p.value.(map[string]interface{}); isMap && len(p.keys) == len(segs) && hasPrefix(p.keys, segs) {
+				whole, patches, values = m, nil, nil
+				continue
+			}
+			rel, ok := trimPrefix(p.keys, segs)
+			switch {
+			case !ok:
+			case slicePrefix(merged, rel) > 0:
+				patches = append(patches, patch{keys: rel, value: p.value})
+			default:
+				values = append(values, patch{keys: rel, value: p.value})
+			}
+		}
+
+		var replaced [][]string
+		for _, r := range s.replaced {
+			if hasPrefix(segs, r) {
+				// Delete o Replace sobre la sección descartan lo que aportaron las fuentes anteriores
+				res = nil
+			} else if rel, ok := trimPrefix(r, segs); ok {
+				replaced = append(replaced, rel)
+			}
+		}
+		if whole != nil {
+			res = nil
+		}
+
+		data := whole
+		if keys, ok := local[i]; ok && data == nil {
+			v, found := lookup(s.data, keys)
+			m, isMap := v.(map[string]interface{})
+			switch {
+			case found && isMap:
+				data = m
+			case found || replacesPath(s.data, keys):
+				// La fuente reemplaza la sección: las anteriores dejan de aportar valores
+				res = nil
+				continue
+			}
+		}
+		if data == nil {
+			if len(patches)+len(values)+len(replaced) == 0 {
+				continue
+			}
+			data = make(map[string]interface{})
+		}
+
+		if len(values) > 0 {
+			data = cloneValue(data).(map[string]interface{})
+			for _, p := range values {
+				_ = assign(data, p.keys, cloneValue(p.value))
+			}
+		}
+
+		cp := *s
+		cp.data, cp.patches, cp.replaced = data, patches, replaced
+		cp.path, cp.files = "", nil
+		res = append(res, &cp)
+	}
+	return res
+}
+
+// rlock toma el bloqueo de lectura, salvo en las vistas de solo lectura, cuyos campos no cambian
+// una vez creadas.
+func (c *Config) rlock() {
+	if !c.readOnly {
+		c.mu.RLock()
+	}
+}
+
+// runlock libera el bloqueo tomado con rlock.
+func (c *Config) runlock() {
+	if !c.readOnly {
+		c.mu.RUnlock()
+	}
+}
+
+// view crea una configuración que comparte data. Debe llamarse con el bloqueo tomado.
+func (c *Config) view(data map[string]interface{}, secrets [][]string) *Config {
+	return &Config{
+		data:      data,
+		refs:      c.refs,
+		secrets:   append([][]string(nil), secrets...),
+		resolvers: append([]resolverEntry(nil), c.resolvers...),
+		opts:      c.opts,
+	}
+}
+
+// refsData retorna el árbol contra el que se resuelven las referencias ${clave}.
+func (c *Config) refsData() map[string]interface{} {
+	if c.refs != nil {
+		return c.refs
+	}
+	return c.data
+}
+
+// trimPrefix quita el prefijo de la ruta si coincide, admitiendo el comodín "*" en la ruta.
+func trimPrefix(path, prefix []string) ([]string, bool) {
+	if len(path) <= len(prefix) {
+		return nil, false
+	}
+	for i, seg := range prefix {
+		if path[i] != seg && path[i] != wildcard {
+			return nil, false
+		}
+	}
+	return path[len(prefix):], true
+}
@@ -0,0 +1,306 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ------------------------------------------------------------------------------------------------
+// KeyError
+// ------------------------------------------------------------------------------------------------
+
+// KeyError describe por qué no se pudo obtener una clave: no existe (ErrKeyNotFound) o su valor
+// no es convertible al tipo solicitado (ErrTypeMismatch, ErrOverflow). Type contiene el tipo
+// encontrado en la configuración y está vacío cuando la clave no existe.
+type KeyError struct {
+	Key  string
+	Type string
+	Err  error
+}
+
+func (e *KeyError) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("config: key %q: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("config: key %q (found %s): %v", e.Key, e.Type, e.Err)
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// ------------------------------------------------------------------------------------------------
+// Implementation Methods
+// ------------------------------------------------------------------------------------------------
+
+// GetE devuelve una copia del valor asociado a la clave o ErrKeyNotFound si no existe.
+func (c *Config) GetE(keys string) (interface{}, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return cloneValue(v), nil
+}
+
+// GetStringE devuelve el valor de la clave como string.
+// Retorna ErrKeyNotFound si no existe o ErrTypeMismatch si es un mapa o un slice.
+func (c *Config) GetStringE(keys string) (string, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return "", err
+	}
+	return convertE(keys, v, c.opts.StringPolicy.toStringE)
+}
+
+// GetIntE devuelve el valor de la clave como int.
+// A diferencia de GetInt, distingue una clave inexistente de un valor no convertible.
+func (c *Config) GetIntE(keys string) (int, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return 0, err
+	}
+	return convertE(keys, v, toIntE)
+}
+
+// GetFloatE devuelve el valor de la clave como float64.
+func (c *Config) GetFloatE(keys string) (float64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return 0, err
+	}
+	return convertE(keys, v, toFloat64E)
+}
+
+// GetBoolE devuelve el valor de la clave como bool.
+func (c *Config) GetBoolE(keys string) (bool, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return false, err
+	}
+	return convertE(keys, v, toBoolE)
+}
+
+// GetMapE devuelve una copia del mapa asociado a la clave.
+// Retorna ErrTypeMismatch si el valor no es un mapa.
+func (c *Config) GetMapE(keys string) (map[string]interface{}, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return cloneValue(m).(map[string]interface{}), nil
+}
+
+// GetMapStringE convierte cada valor del mapa a string, reportando la primera clave no convertible.
+func (c *Config) GetMapStringE(keys string) (map[string]string, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, c.opts.StringPolicy.toStringE)
+}
+
+// GetMapIntE convierte cada valor del mapa a int, reportando la primera clave no convertible.
+func (c *Config) GetMapIntE(keys string) (map[string]int, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, toIntE)
+}
+
+// GetMapFloatE convierte cada valor del mapa a float64, reportando la primera clave no convertible.
+func (c *Config) GetMapFloatE(keys string) (map[string]float64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, toFloat64E)
+}
+
+// GetMapBoolE convierte cada valor del mapa a bool, reportando la primera clave no convertible.
+func (c *Config) GetMapBoolE(keys string) (map[string]bool, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, toBoolE)
+}
+
+// GetSliceE devuelve una copia del slice asociado a la clave.
+// Retorna ErrTypeMismatch si el valor no es un slice.
+func (c *Config) GetSliceE(keys string) ([]interface{}, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return cloneValue(s).([]interface{}), nil
+}
+
+// GetSliceStringE convierte cada elemento del slice a string, reportando el primer índice no convertible.
+func (c *Config) GetSliceStringE(keys string) ([]string, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, c.opts.StringPolicy.toStringE)
+}
+
+// GetSliceIntE convierte cada elemento del slice a int, reportando el primer índice no convertible.
+func (c *Config) GetSliceIntE(keys string) ([]int, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, toIntE)
+}
+
+// GetSliceFloatE convierte cada elemento del slice a float64, reportando el primer índice no convertible.
+func (c *Config) GetSliceFloatE(keys string) ([]float64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, toFloat64E)
+}
+
+// GetSliceBoolE convierte cada elemento del slice a bool, reportando el primer índice no convertible.
+func (c *Config) GetSliceBoolE(keys string) ([]bool, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, toBoolE)
+}
+
+// ------------------------------------------------------------------------------------------------
+// Helpers
+// ------------------------------------------------------------------------------------------------
+
+// getRawValueE es la variante de getRawValue que describe por qué no se encontró la clave.
+func (c *Config) getRawValueE(key string) (interface{}, error) {
+	if key == "" {
+		return nil, ErrKeyEmpty
+	}
+	v, ok := c.lookupKey(key)
+	if !ok {
+		return nil, &KeyError{Key: key, Err: ErrKeyNotFound}
+	}
+	r, err := c.resolve(v)
+	if err != nil {
+		return nil, &KeyError{Key: key, Type: typeName(v), Err: err}
+	}
+	return r, nil
+}
+
+func (c *Config) getRawMapE(key string) (map[string]interface{}, error) {
+	v, err := c.getRawValueE(key)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, &KeyError{Key: key, Type: typeName(v), Err: fmt.Errorf("%w: not a map", ErrTypeMismatch)}
+	}
+	return m, nil
+}
+
+func (c *Config) getRawSliceE(key string) ([]interface{}, error) {
+	v, err := c.getRawValueE(key)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.([]interface{})
+	if !ok {
+		return nil, &KeyError{Key: key, Type: typeName(v), Err: fmt.Errorf("%w: not a slice", ErrTypeMismatch)}
+	}
+	return s, nil
+}
+
+// convertE aplica el conversor y envuelve el error con la clave y el tipo encontrado.
+func convertE[T any](key string, v interface{}, conv func(interface{}) (T, error)) (T, error) {
+	r, err := conv(v)
+	if err != nil {
+		return r, &KeyError{Key: key, Type: typeName(v), Err: err}
+	}
+	return r, nil
+}
+
+func convertMapE[T any](key, sep string, m map[string]interface{}, conv func(interface{}) (T, error)) (map[string]T, error) {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	res := make(map[string]T, len(m))
+	for _, k := range names {
+		r, err := convertE(key+sep+k, m[k], conv)
+		if err != nil {
+			return nil, err
+		}
+		res[k] = r
+	}
+	return res, nil
+}
+
+func convertSliceE[T any](key, sep string, s []interface{}, conv func(interface{}) (T, error)) ([]T, error) {
+	res := make([]T, 0, len(s))
+	for i, v := range s {
+		r, err := convertE(fmt.Sprintf("%s%s%d", key, sep, i), v, conv)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// toIntE convierte un valor a int detectando el desbordamiento en plataformas de 32 bits.
+func toIntE(value interface{}) (int, error) {
+	i, err := toInt64E(value)
+	if err != nil {
+		return 0, err
+	}
+	if i < math.MinInt || i > math.MaxInt {
+		return 0, fmt.Errorf("%w: %d does not fit in int", ErrOverflow, i)
+	}
+	return int(i), nil
+}
+
+func typeName(v interface{}) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%T", v)
+}
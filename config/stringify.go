@@ -0,0 +1,51 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ------------------------------------------------------------------------------------------------
+// StringPolicy
+// ------------------------------------------------------------------------------------------------
+
+// StringPolicy define cómo GetString, sus variantes, Unmarshal, Get[string] y la interpolación
+// convierten los valores a string.
+type StringPolicy int
+
+const (
+	// StringShortest escribe los flotantes con la menor cantidad de dígitos que conserva su valor
+	// (0.1 => "0.1", 1e-9 => "0.000000001"). Los mapas y slices producen una cadena vacía.
+	StringShortest StringPolicy = iota
+	// StringJSON se comporta como StringShortest y además representa los mapas y slices en JSON.
+	StringJSON
+	// StringLegacy conserva el formato anterior: flotantes con %f ("0.100000") y cadena vacía
+	// para mapas y slices.
+	StringLegacy
+)
+
+// toString convierte el valor según la política.
+func (p StringPolicy) toString(value interface{}) string {
+	s, _ := p.toStringE(value)
+	return s
+}
+
+// toStringE convierte el valor según la política, retornando ErrTypeMismatch para los valores
+// que la política no admite.
+func (p StringPolicy) toStringE(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case float32, float64:
+		if p == StringLegacy {
+			return fmt.Sprintf("%f", v), nil
+		}
+	case map[string]interface{}, []interface{}:
+		if p == StringJSON {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", fmt.Errorf("%w: %v", ErrTypeMismatch, err)
+			}
+			return string(data), nil
+		}
+	}
+	return toStringE(value)
+}
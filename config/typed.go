@@ -0,0 +1,288 @@
+package config
+
+import (
+	"net/url"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Implementation Methods
+// ------------------------------------------------------------------------------------------------
+
+// GetDuration devuelve el valor de la clave como time.Duration. Acepta cadenas como "30s" o
+// "1h30m" y enteros, interpretados como nanosegundos. Si no existe o no es convertible, retorna 0.
+func (c *Config) GetDuration(keys string) time.Duration {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return scalarOf(c, keys, toDurationE)
+}
+
+// GetTime devuelve el valor de la clave como time.Time, probando los layouts de
+// Options.TimeLayouts. Si no existe o no es convertible, retorna el tiempo cero.
+func (c *Config) GetTime(keys string) time.Time {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return scalarOf(c, keys, c.toTimeE)
+}
+
+// GetBytesSize devuelve el valor de la clave en bytes. Acepta "512MB" (potencias de 1000),
+// "1.5GiB" (potencias de 1024) y números sin unidad. Si no existe o no es convertible, retorna 0.
+func (c *Config) GetBytesSize(keys string) int64 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return scalarOf(c, keys, toBytesSizeE)
+}
+
+// GetURL devuelve el valor de la clave interpretado con url.Parse.
+// Si no existe, está vacío o no es convertible, retorna nil.
+func (c *Config) GetURL(keys string) *url.URL {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return scalarOf(c, keys, toURLE)
+}
+
+// GetMapDuration convierte cada valor del mapa a time.Duration; los valores no convertibles quedan en 0.
+// Si la clave no existe o no es un mapa, retorna un mapa vacío.
+func (c *Config) GetMapDuration(keys string) map[string]time.Duration {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return mapOf(c, keys, toDurationE)
+}
+
+// GetMapTime convierte cada valor del mapa a time.Time; los valores no convertibles quedan en el tiempo cero.
+// Si la clave no existe o no es un mapa, retorna un mapa vacío.
+func (c *Config) GetMapTime(keys string) map[string]time.Time {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return mapOf(c, keys, c.toTimeE)
+}
+
+// GetMapBytesSize convierte cada valor del mapa a int64; los valores no convertibles quedan en 0.
+// Si la clave no existe o no es un mapa, retorna un mapa vacío.
+func (c *Config) GetMapBytesSize(keys string) map[string]int64 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return mapOf(c, keys, toBytesSizeE)
+}
+
+// GetMapURL convierte cada valor del mapa a *url.URL; los valores no convertibles quedan en nil.
+// Si la clave no existe o no es un mapa, retorna un mapa vacío.
+func (c *Config) GetMapURL(keys string) map[string]*url.URL {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return mapOf(c, keys, toURLE)
+}
+
+// GetSliceDuration convierte cada elemento del slice a time.Duration; los no convertibles quedan en 0.
+// Si la clave no existe o no es un slice, retorna un slice vacío.
+func (c *Config) GetSliceDuration(keys string) []time.Duration {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return sliceOf(c, keys, toDurationE)
+}
+
+// GetSliceTime convierte cada elemento del slice a time.Time; los no convertibles quedan en el tiempo cero.
+// Si la clave no existe o no es un slice, retorna un slice vacío.
+func (c *Config) GetSliceTime(keys string) []time.Time {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return sliceOf(c, keys, c.toTimeE)
+}
+
+// GetSliceBytesSize convierte cada elemento del slice a int64; los no convertibles quedan en 0.
+// Si la clave no existe o no es un slice, retorna un slice vacío.
+func (c *Config) GetSliceBytesSize(keys string) []int64 {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return sliceOf(c, keys, toBytesSizeE)
+}
+
+// GetSliceURL convierte cada elemento del slice a *url.URL; los no convertibles quedan en nil.
+// Si la clave no existe o no es un slice, retorna un slice vacío.
+func (c *Config) GetSliceURL(keys string) []*url.URL {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	return sliceOf(c, keys, toURLE)
+}
+
+// GetDurationE devuelve el valor de la clave como time.Duration, o el motivo por el que no es posible.
+func (c *Config) GetDurationE(keys string) (time.Duration, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return 0, err
+	}
+	return convertE(keys, v, toDurationE)
+}
+
+// GetTimeE devuelve el valor de la clave como time.Time, o el motivo por el que no es posible.
+func (c *Config) GetTimeE(keys string) (time.Time, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return convertE(keys, v, c.toTimeE)
+}
+
+// GetBytesSizeE devuelve el valor de la clave como int64, o el motivo por el que no es posible.
+func (c *Config) GetBytesSizeE(keys string) (int64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return 0, err
+	}
+	return convertE(keys, v, toBytesSizeE)
+}
+
+// GetURLE devuelve el valor de la clave como *url.URL, o el motivo por el que no es posible.
+func (c *Config) GetURLE(keys string) (*url.URL, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	v, err := c.getRawValueE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertE(keys, v, toURLE)
+}
+
+// GetMapDurationE convierte cada valor del mapa a time.Duration, reportando la primera clave no convertible.
+func (c *Config) GetMapDurationE(keys string) (map[string]time.Duration, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, toDurationE)
+}
+
+// GetMapTimeE convierte cada valor del mapa a time.Time, reportando la primera clave no convertible.
+func (c *Config) GetMapTimeE(keys string) (map[string]time.Time, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, c.toTimeE)
+}
+
+// GetMapBytesSizeE convierte cada valor del mapa a int64, reportando la primera clave no convertible.
+func (c *Config) GetMapBytesSizeE(keys string) (map[string]int64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, toBytesSizeE)
+}
+
+// GetMapURLE convierte cada valor del mapa a *url.URL, reportando la primera clave no convertible.
+func (c *Config) GetMapURLE(keys string) (map[string]*url.URL, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	m, err := c.getRawMapE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertMapE(keys, c.opts.Separator, m, toURLE)
+}
+
+// GetSliceDurationE convierte cada elemento del slice a time.Duration, reportando el primer índice no convertible.
+func (c *Config) GetSliceDurationE(keys string) ([]time.Duration, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, toDurationE)
+}
+
+// GetSliceTimeE convierte cada elemento del slice a time.Time, reportando el primer índice no convertible.
+func (c *Config) GetSliceTimeE(keys string) ([]time.Time, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, c.toTimeE)
+}
+
+// GetSliceBytesSizeE convierte cada elemento del slice a int64, reportando el primer índice no convertible.
+func (c *Config) GetSliceBytesSizeE(keys string) ([]int64, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, toBytesSizeE)
+}
+
+// GetSliceURLE convierte cada elemento del slice a *url.URL, reportando el primer índice no convertible.
+func (c *Config) GetSliceURLE(keys string) ([]*url.URL, error) {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	s, err := c.getRawSliceE(keys)
+	if err != nil {
+		return nil, err
+	}
+	return convertSliceE(keys, c.opts.Separator, s, toURLE)
+}
+
+// ------------------------------------------------------------------------------------------------
+// Helpers
+// ------------------------------------------------------------------------------------------------
+
+// toTimeE convierte el valor con los layouts configurados.
+func (c *Config) toTimeE(value interface{}) (time.Time, error) {
+	return toTimeE(value, c.opts.TimeLayouts)
+}
+
+// scalarOf convierte el valor de la clave, retornando el valor cero si no existe o no es convertible.
+func scalarOf[T any](c *Config, key string, conv func(interface{}) (T, error)) T {
+	var zero T
+	v, ok := c.getRawValue(key)
+	if !ok {
+		return zero
+	}
+	r, err := conv(v)
+	if err != nil {
+		return zero
+	}
+	return r
+}
+
+// mapOf convierte cada valor del mapa de la clave, usando el valor cero para los no convertibles.
+func mapOf[T any](c *Config, key string, conv func(interface{}) (T, error)) map[string]T {
+	res := map[string]T{}
+	m, ok := c.getRawMap(key)
+	if !ok {
+		return res
+	}
+	for k, v := range m {
+		res[k], _ = conv(v)
+	}
+	return res
+}
+
+// sliceOf convierte cada elemento del slice de la clave, usando el valor cero para los no convertibles.
+func sliceOf[T any](c *Config, key string, conv func(interface{}) (T, error)) []T {
+	s, ok := c.getRawSlice(key)
+	if !ok {
+		return []T{}
+	}
+	res := make([]T, 0, len(s))
+	for _, v := range s {
+		r, _ := conv(v)
+		res = append(res, r)
+	}
+	return res
+}
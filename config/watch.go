@@ -0,0 +1,201 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+)
+
+const (
+	defaultWatchInterval = 2 * time.Second
+)
+
+// WatchOptions configura la recarga automática de los archivos cargados con LoadFile.
+// Ante un error de lectura o parseo se conserva la última configuración válida y se invoca OnError.
+type WatchOptions struct {
+	Interval time.Duration                // Frecuencia de revisión de los archivos (por defecto 2s)
+	OnError  func(path string, err error) // Opcional: recibe los errores de recarga
+}
+
+// subscription asocia una clave con la función a notificar cuando su valor cambie.
+type subscription struct {
+	keys []string
+	fn   func(old, new interface{})
+}
+
+// change es una notificación pendiente, calculada con el bloqueo tomado y entregada sin él.
+type change struct {
+	fn       func(old, new interface{})
+	old, new interface{}
+}
+
+// fileState guarda la última versión observada de un archivo vigilado.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// OnChange registra una función que se invoca cuando el valor de la clave cambia por una recarga
+// o cualquier otra modificación. Recibe copias del valor anterior y del nuevo (nil si no existía).
+// Con la clave vacía se notifica cualquier cambio, entregando la configuración completa.
+// En una vista de Snapshot, que no cambia, no tiene efecto.
+func (c *Config) OnChange(key string, fn func(old, new interface{})) {
+	if c.readOnly {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var keys []string
+	if key != "" {
+		keys = splitKey(key, c.opts.Separator)
+	}
+	c.subs = append(c.subs, subscription{keys: keys, fn: fn})
+}
+
+// Reload vuelve a leer todos los archivos cargados con LoadFile y reemplaza sus valores de forma atómica.
+// Si algún archivo no puede leerse o parsearse, no se aplica ningún cambio y se retorna el error.
+func (c *Config) Reload() error {
+	c.rlock()
+	files := c.watchedSources()
+	c.runlock()
+
+	return c.reload(files)
+}
+
+// Watch revisa periódicamente los archivos cargados con LoadFile, junto con los que incorporan con
+// include y $ref, y los recarga cuando cambian, hasta que el contexto se cancele. La revisión se
+// ejecuta en segundo plano.
+func (c *Config) Watch(ctx context.Context, opts WatchOptions) error {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultWatchInterval
+	}
+
+	c.rlock()
+	files := c.watchedSources()
+	c.runlock()
+
+	if len(files) == 0 {
+		return ErrNothingToWatch
+	}
+
+	// El estado se guarda por fuente, ya que un archivo incluido puede pertenecer a varias
+	states := make(map[[2]string]fileState)
+	modified := func(s *source) bool {
+		changed := false
+		for _, path := range c.watchedFiles(s.path) {
+			key := [2]string{s.path, path}
+			if st := statFile(path); st != states[key] {
+				states[key] = st
+				changed = true
+			}
+		}
+		return changed
+	}
+	for _, s := range files {
+		modified(s)
+	}
+
+	go func() {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			for _, s := range files {
+				if !modified(s) {
+					continue
+				}
+				if err := c.reload([]*source{s}); err != nil && opts.OnError != nil {
+					opts.OnError(s.path, err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// reload relee y parsea los archivos de las fuentes indicadas, con los mismos perfiles y
+// estrategia con los que se cargaron, y, si todos son válidos, reemplaza sus fuentes.
+func (c *Config) reload(files []*source) error {
+	fresh := make(map[string]*source, len(files))
+	for _, s := range files {
+		data, err := os.ReadFile(s.path)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrReadFile, err)
+		}
+
+		cp := *s
+		if cp.data, cp.files, err = c.parseFile(s, data); err != nil {
+			return err
+		}
+		fresh[s.path] = &cp
+	}
+
+	return c.update(func(sources []*source) ([]*source, error) {
+		res := make([]*source, len(sources))
+		for i, s := range sources {
+			res[i] = s
+			if f, ok := fresh[s.path]; ok {
+				cp := *s
+				cp.data, cp.files = f.data, f.files
+				res[i] = &cp
+			}
+		}
+		return res, nil
+	})
+}
+
+// watchedSources retorna las fuentes leídas de un archivo, que pueden recargarse.
+// Debe llamarse con el bloqueo tomado.
+func (c *Config) watchedSources() []*source {
+	var files []*source
+	for _, s := range c.sources {
+		if s.path != "" {
+			files = append(files, s)
+		}
+	}
+	return files
+}
+
+// watchedFiles retorna el archivo de la fuente y los que incorporó en su última lectura.
+func (c *Config) watchedFiles(path string) []string {
+	c.rlock()         // Bloqueo de lectura
+	defer c.runlock() // Liberar al salir
+	for _, s := range c.sources {
+		if s.path == path {
+			return append([]string{path}, s.files...)
+		}
+	}
+	return []string{path}
+}
+
+// changes compara la configuración anterior con la nueva y retorna las notificaciones de los
+// suscriptores cuya clave cambió. Debe llamarse con el bloqueo tomado.
+func (c *Config) changes(old, new map[string]interface{}) []change {
+	var res []change
+	for _, sub := range c.subs {
+		before, _ := lookup(old, sub.keys)
+		after, _ := lookup(new, sub.keys)
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+		res = append(res, change{fn: sub.fn, old: cloneValue(before), new: cloneValue(after)})
+	}
+	return res
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size()}
+}
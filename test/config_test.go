@@ -1,8 +1,22 @@
 package test
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/edro08/go-utils/config"
 )
@@ -97,3 +111,1724 @@ func TestConfig_KeyEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_LoadBytesFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format config.Format
+		data   string
+	}{
+		{
+			name:   "YAML",
+			format: config.FormatYAML,
+			data:   "server:\n  port: 8080\n  name: go-utils\n",
+		},
+		{
+			name:   "JSON",
+			format: config.FormatJSON,
+			data:   `{"server": {"port": 8080, "name": "go-utils"}}`,
+		},
+		{
+			name:   "TOML",
+			format: config.FormatTOML,
+			data:   "[server]\nport = 8080\nname = \"go-utils\"\n",
+		},
+		{
+			name:   "ENV",
+			format: config.FormatEnv,
+			data:   "# comentario\nserver.port=8080\nexport server.name=\"go-utils\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.New(config.Options{})
+			if err := cfg.LoadBytes([]byte(tt.data), tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if got := cfg.GetInt("server.port"); got != 8080 {
+				t.Errorf("server.port = %v, want = %v", got, 8080)
+			}
+			if got := cfg.GetString("server.name"); got != "go-utils" {
+				t.Errorf("server.name = %q, want = %q", got, "go-utils")
+			}
+		})
+	}
+
+	// El contenido tras el primer valor JSON es un error
+	for _, data := range []string{`{"a":1} garbage`, `{"a":1} {"b":2}`} {
+		cfg := config.New(config.Options{})
+		if err := cfg.LoadBytes([]byte(data), config.FormatJSON); !errors.Is(err, config.ErrParseJSON) {
+			t.Errorf("LoadBytes(%q) error = %v, want = %v", data, err, config.ErrParseJSON)
+		}
+	}
+	cfg := config.New(config.Options{})
+	if err := cfg.LoadBytes([]byte("{\"a\":1}\n\n"), config.FormatJSON); err != nil {
+		t.Errorf("LoadBytes() with trailing whitespace error = %v", err)
+	}
+}
+
+func TestConfig_LoadEnv(t *testing.T) {
+	t.Setenv("APP_DATABASE__HOST", "db.local")
+	t.Setenv("APP_DATABASE__PORT", "5432")
+	t.Setenv("APP_DEBUG", "TRUE")
+	t.Setenv("OTHER_VALUE", "ignored")
+
+	cfg := config.New(config.Options{})
+	if err := cfg.LoadBytes([]byte("database:\n  host: localhost\n  user: admin\n"), config.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.LoadEnv("APP"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cfg.GetString("database.host"); got != "db.local" {
+		t.Errorf("database.host = %q, want = %q", got, "db.local")
+	}
+	if got := cfg.GetInt("database.port"); got != 5432 {
+		t.Errorf("database.port = %v, want = %v", got, 5432)
+	}
+	if got := cfg.GetString("database.user"); got != "admin" {
+		t.Errorf("database.user = %q, want = %q", got, "admin")
+	}
+	if got := cfg.GetBool("debug"); got != true {
+		t.Errorf("debug = %v, want = %v", got, true)
+	}
+	if cfg.HasKey("value", "") {
+		t.Error("variables without the prefix must be ignored")
+	}
+
+	// Los valores se conservan tal como están en el entorno
+	t.Setenv("APP_VERSION", "1.10")
+	t.Setenv("APP_ZIP", "01234")
+	t.Setenv("APP_PASS", "1e3")
+	if err := cfg.LoadEnv("APP"); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"version": "1.10", "zip": "01234", "pass": "1e3"} {
+		if got := cfg.GetString(key); got != want {
+			t.Errorf("%s = %q, want = %q", key, got, want)
+		}
+	}
+}
+
+func TestConfig_LoadFlags(t *testing.T) {
+	cfg := config.New(config.Options{})
+	if err := cfg.LoadBytes([]byte("server:\n  port: 8080\n  name: go-utils\n"), config.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.BindFlags(fs)
+	if fs.Lookup("server.port") == nil || fs.Lookup("server.name") == nil {
+		t.Fatal("BindFlags must register a flag per existing key")
+	}
+	if err := fs.Parse([]string{"-server.port=9090"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.LoadFlags(fs); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetInt("server.port"); got != 9090 {
+		t.Errorf("server.port = %v, want = %v", got, 9090)
+	}
+	if got := cfg.GetString("server.name"); got != "go-utils" {
+		t.Errorf("server.name = %q, want = %q", got, "go-utils")
+	}
+
+	if err := cfg.LoadArgs([]string{"serve", "--server.name=api", "--offset", "-1", "--debug"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetString("server.name"); got != "api" {
+		t.Errorf("server.name = %q, want = %q", got, "api")
+	}
+	if got := cfg.GetInt("offset"); got != -1 {
+		t.Errorf("offset = %v, want = %v", got, -1)
+	}
+	if cfg.HasKey("1", "") {
+		t.Error("a negative number must be read as the value of the previous flag")
+	}
+	if !cfg.GetBool("debug") {
+		t.Error("debug = false, want = true")
+	}
+}
+
+func TestConfig_LayersPrecedence(t *testing.T) {
+	t.Setenv("APP_SERVER__PORT", "8081")
+
+	cfg := config.New(config.Options{})
+	if err := cfg.LoadEnv("APP"); err != nil {
+		t.Fatal(err)
+	}
+	// El archivo se carga después, pero la capa de entorno conserva la precedencia
+	if err := cfg.LoadFile("app.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cfg.GetInt("server.port"); got != 8081 {
+		t.Errorf("server.port = %v, want = %v", got, 8081)
+	}
+	if got, _ := cfg.Origin("server.port"); got != (config.Origin{Layer: config.LayerEnv, Source: "APP"}) {
+		t.Errorf("Origin(server.port) = %v, want = env:APP", got)
+	}
+	if got, _ := cfg.Origin("server.name"); got != (config.Origin{Layer: config.LayerFile, Source: "app.yaml"}) {
+		t.Errorf("Origin(server.name) = %v, want = file:app.yaml", got)
+	}
+
+	if err := cfg.Set("server.port", 9000); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := cfg.Origin("server.port"); got.Layer != config.LayerRuntime {
+		t.Errorf("Origin(server.port) = %v, want = runtime", got)
+	}
+	if _, ok := cfg.Origin("no.exists"); ok {
+		t.Error("Origin(no.exists) must not be found")
+	}
+}
+
+func TestConfig_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  port: 8080\n  name: go-utils\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.New(config.Options{})
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	var portChanges, nameChanges int
+	cfg.OnChange("server.port", func(old, new interface{}) {
+		portChanges++
+		if old != 8080 || new != 9090 {
+			t.Errorf("OnChange(server.port) old = %v, new = %v", old, new)
+		}
+	})
+	cfg.OnChange("server.name", func(old, new interface{}) { nameChanges++ })
+
+	if err := os.WriteFile(path, []byte("server:\n  port: 9090\n  name: go-utils\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetInt("server.port"); got != 9090 {
+		t.Errorf("server.port = %v, want = %v", got, 9090)
+	}
+	if portChanges != 1 || nameChanges != 0 {
+		t.Errorf("notifications: port = %d, name = %d, want = 1, 0", portChanges, nameChanges)
+	}
+
+	// Una edición inválida conserva la última configuración válida
+	if err := os.WriteFile(path, []byte("server: [port: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Reload(); !errors.Is(err, config.ErrParseYAML) {
+		t.Errorf("Reload() error = %v, want = %v", err, config.ErrParseYAML)
+	}
+	if got := cfg.GetInt("server.port"); got != 9090 {
+		t.Errorf("server.port = %v, want = %v", got, 9090)
+	}
+}
+
+func TestConfig_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  port: 8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.New(config.Options{})
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := make(chan interface{}, 1)
+	cfg.OnChange("server.port", func(old, new interface{}) { changed <- new })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cfg.Watch(ctx, config.WatchOptions{Interval: 10 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte("server:\n  port: 18080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-changed:
+		if got != 18080 {
+			t.Errorf("server.port = %v, want = %v", got, 18080)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch() did not report the change")
+	}
+}
+
+func TestConfig_Unmarshal(t *testing.T) {
+	type database struct {
+		Host    string        `config:"host"`
+		Port    uint16        `config:"port"`
+		Timeout time.Duration `config:"timeout"`
+	}
+	type settings struct {
+		Name      string               `config:"name"`
+		Database  *database            `config:"database"`
+		Replicas  []database           `config:"replicas"`
+		Limits    map[string]int       `config:"limits"`
+		Debug     bool                 // Sin etiqueta: se asocia por nombre
+		Ignored   string               `config:"-"`
+		Endpoints map[string]*database `config:"endpoints"`
+	}
+
+	cfg := config.New(config.Options{})
+	err := cfg.LoadBytes([]byte(`
+app:
+  name: go-utils
+  debug: true
+  ignored: value
+  database:
+    host: localhost
+    port: 5432
+    timeout: 5s
+  replicas:
+    - host: replica-1
+      port: 5433
+  limits:
+    rps: 100
+  endpoints:
+    main:
+      host: main.local
+`), config.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got settings
+	if err := cfg.Unmarshal("app", &got); err != nil {
+		t.Fatal(err)
+	}
+	want := settings{
+		Name:      "go-utils",
+		Database:  &database{Host: "localhost", Port: 5432, Timeout: 5 * time.Second},
+		Replicas:  []database{{Host: "replica-1", Port: 5433}},
+		Limits:    map[string]int{"rps": 100},
+		Debug:     true,
+		Endpoints: map[string]*database{"main": {Host: "main.local"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() got = %+v, want = %+v", got, want)
+	}
+
+	// Los errores de conversión se reportan por campo
+	if err := cfg.Set("app.database.port", 70000); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("app.database.timeout", "soon"); err != nil {
+		t.Fatal(err)
+	}
+	err = cfg.Unmarshal("app", &got)
+	if !errors.Is(err, config.ErrOverflow) || !errors.Is(err, config.ErrTypeMismatch) {
+		t.Fatalf("Unmarshal() error = %v, want overflow and type mismatch", err)
+	}
+	for _, path := range []string{"app.database.port", "app.database.timeout"} {
+		if !strings.Contains(err.Error(), path) {
+			t.Errorf("Unmarshal() error = %v, want path %q", err, path)
+		}
+	}
+}
+
+func TestConfig_GetE(t *testing.T) {
+	cfg := config.New(config.Options{})
+	err := cfg.LoadBytes([]byte("server:\n  port: abc\n  ratio: 0.5\nlimits:\n  rps: 100\n  burst: many\n"), config.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		fn      func() error
+		wantErr error
+		wantKey string
+		wantTyp string
+	}{
+		{
+			name:    "GetIntE(): key not found",
+			fn:      func() error { _, err := cfg.GetIntE("server.missing"); return err },
+			wantErr: config.ErrKeyNotFound,
+			wantKey: "server.missing",
+		},
+		{
+			name:    "GetIntE(): type mismatch",
+			fn:      func() error { _, err := cfg.GetIntE("server.port"); return err },
+			wantErr: config.ErrTypeMismatch,
+			wantKey: "server.port",
+			wantTyp: "string",
+		},
+		{
+			name:    "GetIntE(): fractional part",
+			fn:      func() error { _, err := cfg.GetIntE("server.ratio"); return err },
+			wantErr: config.ErrTypeMismatch,
+			wantKey: "server.ratio",
+			wantTyp: "float64",
+		},
+		{
+			name:    "GetMapIntE(): element mismatch",
+			fn:      func() error { _, err := cfg.GetMapIntE("limits"); return err },
+			wantErr: config.ErrTypeMismatch,
+			wantKey: "limits.burst",
+			wantTyp: "string",
+		},
+		{
+			name:    "GetSliceStringE(): not a slice",
+			fn:      func() error { _, err := cfg.GetSliceStringE("limits"); return err },
+			wantErr: config.ErrTypeMismatch,
+			wantKey: "limits",
+			wantTyp: "map[string]interface {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fn()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want = %v", err, tt.wantErr)
+			}
+			var keyErr *config.KeyError
+			if !errors.As(err, &keyErr) {
+				t.Fatalf("error = %v, want *config.KeyError", err)
+			}
+			if keyErr.Key != tt.wantKey || keyErr.Type != tt.wantTyp {
+				t.Errorf("KeyError = {%q, %q}, want = {%q, %q}", keyErr.Key, keyErr.Type, tt.wantKey, tt.wantTyp)
+			}
+		})
+	}
+
+	if got, err := cfg.GetFloatE("server.ratio"); err != nil || got != 0.5 {
+		t.Errorf("GetFloatE(server.ratio) = %v, %v, want = 0.5, nil", got, err)
+	}
+}
+
+func TestConfig_DefaultsAndRequire(t *testing.T) {
+	cfg := config.New(config.Options{})
+	if err := cfg.SetDefaults(map[string]interface{}{
+		"server.port":    8080,
+		"server.timeout": "30s",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.LoadFile("app.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetDefault("server.name", "default-name"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cfg.GetString("server.timeout"); got != "30s" {
+		t.Errorf("server.timeout = %q, want = %q", got, "30s")
+	}
+	// El archivo tiene precedencia sobre los valores por defecto
+	if got := cfg.GetString("server.name"); got != "go-utils" {
+		t.Errorf("server.name = %q, want = %q", got, "go-utils")
+	}
+
+	if err := cfg.Require("server.port", "server.name"); err != nil {
+		t.Errorf("Require() error = %v, want = nil", err)
+	}
+	err := cfg.Require("server.port", "database.dsn", "database.user")
+	var missing *config.MissingKeysError
+	if !errors.As(err, &missing) || !errors.Is(err, config.ErrKeyNotFound) {
+		t.Fatalf("Require() error = %v, want *config.MissingKeysError", err)
+	}
+	if want := []string{"database.dsn", "database.user"}; !reflect.DeepEqual(missing.Keys, want) {
+		t.Errorf("MissingKeysError.Keys = %v, want = %v", missing.Keys, want)
+	}
+}
+
+func TestConfig_Schema(t *testing.T) {
+	cfg := config.New(config.Options{})
+	err := cfg.LoadBytes([]byte(`
+server:
+  port: 70000
+  name: ""
+log:
+  level: trace
+servers:
+  - host: a.local
+  - host: ""
+`), config.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := config.NewSchema().
+		Key("server.port", config.Type(config.Int), config.Min(1), config.Max(65535)).
+		Key("server.name", config.NonEmpty()).
+		Key("log.level", config.Enum("debug", "info", "warn", "error")).
+		Key("servers.*.host", config.Pattern(`^[a-z.]+$`)).
+		Key("database.dsn", config.Required())
+
+	err = cfg.SetSchema(schema)
+	var verr *config.ValidationError
+	if !errors.As(err, &verr) || !errors.Is(err, config.ErrValidation) {
+		t.Fatalf("SetSchema() error = %v, want *config.ValidationError", err)
+	}
+	var keys []string
+	for _, v := range verr.Violations {
+		keys = append(keys, v.Key)
+	}
+	want := []string{"server.port", "server.name", "log.level", "servers.1.host", "database.dsn"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("violations = %v, want = %v", keys, want)
+	}
+
+	// Con un esquema válido asociado, los cambios inválidos se rechazan
+	js := []byte(`{
+		"type": "object",
+		"required": ["server"],
+		"properties": {
+			"server": {
+				"type": "object",
+				"properties": {"port": {"type": "integer", "minimum": 1, "maximum": 65535}}
+			}
+		}
+	}`)
+	schema, err = config.ParseJSONSchema(js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("server.port", 8080); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetSchema(schema); err != nil {
+		t.Fatalf("SetSchema() error = %v", err)
+	}
+	if err := cfg.Set("server.port", 0); !errors.Is(err, config.ErrValidation) {
+		t.Errorf("Set() error = %v, want = %v", err, config.ErrValidation)
+	}
+	if got := cfg.GetInt("server.port"); got != 8080 {
+		t.Errorf("server.port = %v, want = %v", got, 8080)
+	}
+}
+
+func TestConfig_Interpolate(t *testing.T) {
+	t.Setenv("DB_USER", "admin")
+
+	cfg := config.New(config.Options{Interpolate: true})
+	err := cfg.LoadBytes([]byte(`
+database:
+  host: db.local
+  port: 5432
+  dsn: "postgres://${DB_USER}@${database.host}:${database.port}/${DB_NAME:-app}"
+  port_ref: "${database.port}"
+  literal: "$${DB_USER}"
+cycle:
+  a: "${cycle.b}"
+  b: "${cycle.a}"
+`), config.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := cfg.GetString("database.dsn"), "postgres://admin@db.local:5432/app"; got != want {
+		t.Errorf("database.dsn = %q, want = %q", got, want)
+	}
+	if got := cfg.Get("database.port_ref"); got != 5432 {
+		t.Errorf("database.port_ref = %v, want = %v", got, 5432)
+	}
+	if got, want := cfg.GetString("database.literal"), "${DB_USER}"; got != want {
+		t.Errorf("database.literal = %q, want = %q", got, want)
+	}
+
+	// La interpolación ocurre al leer, por lo que respeta los Set posteriores
+	if err := cfg.Set("database.host", "db.prod"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.GetString("database.dsn"), "postgres://admin@db.prod:5432/app"; got != want {
+		t.Errorf("database.dsn = %q, want = %q", got, want)
+	}
+
+	if _, err := cfg.GetStringE("cycle.a"); !errors.Is(err, config.ErrInterpolationCycle) {
+		t.Errorf("GetStringE(cycle.a) error = %v, want = %v", err, config.ErrInterpolationCycle)
+	}
+
+	// El esquema valida los valores ya interpolados
+	t.Setenv("PORT", "8080")
+	cfg = config.New(config.Options{Interpolate: true})
+	if err := cfg.LoadBytes([]byte(`server: {port: "${PORT}", admin_port: "${server.port}"}`), config.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	schema := config.NewSchema().
+		Key("server.port", config.Type(config.Int), config.Min(1), config.Max(65535)).
+		Key("server.admin_port", config.Type(config.Int))
+	if err := cfg.SetSchema(schema); err != nil {
+		t.Fatalf("SetSchema() error = %v", err)
+	}
+	if err := cfg.Set("server.port", "${MISSING_PORT:-0}"); !errors.Is(err, config.ErrValidation) {
+		t.Errorf("Set() error = %v, want = %v", err, config.ErrValidation)
+	}
+	if got := cfg.GetInt("server.admin_port"); got != 8080 {
+		t.Errorf("server.admin_port = %v, want = %v", got, 8080)
+	}
+}
+
+func TestConfig_Secrets(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	t.Setenv("CONFIG_KEY", base64.StdEncoding.EncodeToString(key))
+
+	secretFile := filepath.Join(t.TempDir(), "db_pass")
+	if err := os.WriteFile(secretFile, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	token, err := config.EncryptSecret(key, "api-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.New(config.Options{})
+	if err := cfg.SetDefaults(map[string]interface{}{
+		"database.host":     "db.local",
+		"database.password": config.FilePrefix + secretFile,
+		"api.token":         token,
+		"api.user":          "admin",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resolver, err := config.AESGCMResolverFromEnv("CONFIG_KEY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.RegisterResolver(config.FilePrefix, config.FileResolver())
+	cfg.RegisterResolver(config.EncryptedPrefix, resolver)
+	cfg.MarkSecret("api.user")
+
+	if got := cfg.GetString("database.password"); got != "s3cr3t" {
+		t.Errorf("database.password = %q, want = %q", got, "s3cr3t")
+	}
+	if got := cfg.GetString("api.token"); got != "api-token" {
+		t.Errorf("api.token = %q, want = %q", got, "api-token")
+	}
+
+	dump := cfg.String()
+	for _, secret := range []string{"s3cr3t", "api-token", "admin", secretFile} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("String() = %s, must not contain %q", dump, secret)
+		}
+	}
+	if !strings.Contains(dump, "db.local") {
+		t.Errorf("String() = %s, want non-secret values", dump)
+	}
+
+	// Llaves de 16, 24 y 32 bytes, en base64 y en crudo; "-" no es base64, por lo que la llave en
+	// crudo no puede leerse como base64
+	for _, size := range []int{16, 24, 32} {
+		key := []byte(strings.Repeat("k-", size/2))
+		token, err := config.EncryptSecret(key, "api-token")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, value := range []string{base64.StdEncoding.EncodeToString(key), string(key)} {
+			t.Setenv("CONFIG_KEY", value)
+			resolver, err := config.AESGCMResolverFromEnv("CONFIG_KEY")
+			if err != nil {
+				t.Fatalf("AESGCMResolverFromEnv(%q) error = %v", value, err)
+			}
+			got, err := resolver.Resolve(strings.TrimPrefix(token, config.EncryptedPrefix))
+			if err != nil || got != "api-token" {
+				t.Errorf("Resolve() with %d-byte key %q = %q, %v, want = %q", size, value, got, err, "api-token")
+			}
+		}
+	}
+}
+
+func TestConfig_Export(t *testing.T) {
+	cfg := config.New(config.Options{})
+	err := cfg.LoadBytes([]byte(`
+server:
+  port: 8080
+  name: go-utils api
+database:
+  password: s3cr3t
+origins: [a.com, b.com]
+`), config.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.MarkSecret("database.password")
+
+	if got, want := cfg.AllKeys(), []string{"database.password", "origins", "server.name", "server.port"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AllKeys() = %v, want = %v", got, want)
+	}
+
+	var flat strings.Builder
+	if err := cfg.Export(&flat, config.FormatEnv); err != nil {
+		t.Fatal(err)
+	}
+	want := "database.password=[REDACTED]\n" +
+		"origins=\"[\\\"a.com\\\",\\\"b.com\\\"]\"\n" +
+		"server.name=\"go-utils api\"\n" +
+		"server.port=8080\n"
+	if flat.String() != want {
+		t.Errorf("Export(FormatEnv) = %q, want = %q", flat.String(), want)
+	}
+
+	// La salida plana puede cargarse de nuevo
+	reloaded := config.New(config.Options{})
+	if err := reloaded.LoadBytes([]byte(flat.String()), config.FormatEnv); err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.GetString("server.name"); got != "go-utils api" {
+		t.Errorf("server.name = %q, want = %q", got, "go-utils api")
+	}
+
+	for _, format := range []config.Format{config.FormatYAML, config.FormatJSON} {
+		var sb strings.Builder
+		if err := cfg.Export(&sb, format); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(sb.String(), "s3cr3t") || !strings.Contains(sb.String(), "go-utils api") {
+			t.Errorf("Export(%s) = %s", format, sb.String())
+		}
+	}
+	// Las fechas de TOML se exportan en RFC 3339
+	cfg = config.New(config.Options{})
+	if err := cfg.LoadBytes([]byte("when = 2024-05-01T10:30:00Z\n"), config.FormatTOML); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.GetString("when"), "2024-05-01T10:30:00Z"; got != want {
+		t.Errorf("GetString(when) = %q, want = %q", got, want)
+	}
+	flat.Reset()
+	if err := cfg.Export(&flat, config.FormatEnv); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := flat.String(), "when=2024-05-01T10:30:00Z\n"; got != want {
+		t.Errorf("Export(FormatEnv) = %q, want = %q", got, want)
+	}
+}
+
+func TestConfig_IndexedKeys(t *testing.T) {
+	cfg := config.New(config.Options{})
+	err := cfg.LoadBytes([]byte(`
+servers:
+  - host: a.local
+    port: 8080
+  - host: b.local
+    port: 8081
+`), config.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cfg.GetString("servers.0.host"); got != "a.local" {
+		t.Errorf("servers.0.host = %q, want = %q", got, "a.local")
+	}
+	if got := cfg.GetInt("servers[1].port"); got != 8081 {
+		t.Errorf("servers[1].port = %v, want = %v", got, 8081)
+	}
+
+	if err := cfg.Set("servers[1].host", "c.local"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.Query("servers.*.host"), []interface{}{"a.local", "c.local"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Query(servers.*.host) = %v, want = %v", got, want)
+	}
+	if got := cfg.GetInt("servers.1.port"); got != 8081 {
+		t.Errorf("servers.1.port = %v, want = %v", got, 8081)
+	}
+
+	if err := cfg.Set("servers.5.host", "x"); !errors.Is(err, config.ErrIndexOutOfRange) {
+		t.Errorf("Set(servers.5.host) error = %v, want = %v", err, config.ErrIndexOutOfRange)
+	}
+	if origin, _ := cfg.Origin("servers.1.host"); origin.Layer != config.LayerRuntime {
+		t.Errorf("Origin(servers.1.host) = %v, want = %v", origin, config.LayerRuntime)
+	}
+
+	// La escritura por índice no congela el slice: el resto sigue las capas inferiores
+	if err := cfg.LoadBytes([]byte("servers:\n  - host: z.local\n"), config.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{map[string]interface{}{"host": "z.local"}}
+	if got := cfg.Get("servers"); !reflect.DeepEqual(got, want) {
+		t.Errorf("servers after LoadBytes = %v, want = %v", got, want)
+	}
+}
+
+func TestConfig_QuotedKeys(t *testing.T) {
+	cfg := config.New(config.Options{})
+	err := cfg.LoadBytes([]byte(`
+hosts:
+  api.example.com:
+    timeout: 30
+`), config.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{
+		`hosts."api.example.com".timeout`,
+		`hosts.api\.example\.com.timeout`,
+		`hosts["api.example.com"].timeout`,
+	} {
+		if got := cfg.GetInt(key); got != 30 {
+			t.Errorf("GetInt(%s) = %v, want = %v", key, got, 30)
+		}
+	}
+	if got := cfg.GetPath([]string{"hosts", "api.example.com", "timeout"}); got != 30 {
+		t.Errorf("GetPath() = %v, want = %v", got, 30)
+	}
+	if got := cfg.GetPath([]string{"hosts", "missing"}); got != nil {
+		t.Errorf("GetPath(missing) = %v, want = nil", got)
+	}
+
+	if err := cfg.Set(`hosts."cdn.example.com".timeout`, 5); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.HasKey(`hosts."cdn.example.com"`, config.Map) {
+		t.Errorf("HasKey(hosts.\"cdn.example.com\") = false, want = true")
+	}
+	want := []string{`hosts."api.example.com".timeout`, `hosts."cdn.example.com".timeout`}
+	if got := cfg.AllKeys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllKeys() = %v, want = %v", got, want)
+	}
+}
+
+func TestConfig_DeleteReplace(t *testing.T) {
+	cfg := config.New(config.Options{})
+	err := cfg.LoadBytes([]byte(`
+server:
+  host: localhost
+  port: 8080
+  tls:
+    cert: a.pem
+    key: a.key
+servers: [a, b, c]
+`), config.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var changed []interface{}
+	cfg.OnChange("server.host", func(old, new interface{}) {
+		changed = append(changed, old, new)
+	})
+
+	if err := cfg.Delete("server.host"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.HasKey("server.host", config.String) {
+		t.Errorf("server.host still exists after Delete")
+	}
+	if want := []interface{}{"localhost", nil}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("OnChange = %v, want = %v", changed, want)
+	}
+
+	if err := cfg.Delete("servers[1]"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.GetSliceString("servers"), []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("servers = %v, want = %v", got, want)
+	}
+	if err := cfg.Delete("missing.key"); err != nil {
+		t.Errorf("Delete(missing.key) error = %v, want = nil", err)
+	}
+	if err := cfg.Delete(""); !errors.Is(err, config.ErrKeyEmpty) {
+		t.Errorf("Delete(\"\") error = %v, want = %v", err, config.ErrKeyEmpty)
+	}
+
+	// Replace no combina con la sección existente
+	if err := cfg.Replace("server.tls", map[string]interface{}{"cert": "b.pem"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.GetMap("server.tls"), map[string]interface{}{"cert": "b.pem"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("server.tls = %v, want = %v", got, want)
+	}
+
+	// Unset solo quita el valor establecido en ejecución
+	if err := cfg.Set("server.port", 9090); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Unset("server.port"); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetInt("server.port"); got != 8080 {
+		t.Errorf("server.port = %v, want = %v", got, 8080)
+	}
+
+	// Unset de una escritura por índice restaura el elemento de las capas inferiores
+	cfg = config.New(config.Options{})
+	if err := cfg.LoadBytes([]byte("servers:\n  - {host: a, port: 0}\n  - {host: b, port: 1}\n"), config.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	original := cfg.Get("servers")
+	if err := cfg.Set("servers.1.host", "c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Unset("servers.1.host"); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetString("servers.1.host"); got != "b" {
+		t.Errorf("servers.1.host after Unset = %q, want = %q", got, "b")
+	}
+	if got := cfg.Get("servers"); !reflect.DeepEqual(got, original) {
+		t.Errorf("servers after Unset = %v, want = %v", got, original)
+	}
+	if origin, _ := cfg.Origin("servers.1.host"); origin.Layer != config.LayerFile {
+		t.Errorf("Origin(servers.1.host) = %v, want = %v", origin, config.LayerFile)
+	}
+
+	// Delete y Replace se mantienen tras Reload y Unset los deshace
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("db: {host: a, port: 1}\nservers: [a, b, c]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg = config.New(config.Options{})
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Replace("db", map[string]interface{}{"host": "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.GetMap("db"), map[string]interface{}{"host": "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("db after Replace and Reload = %v, want = %v", got, want)
+	}
+	if err := cfg.Unset("db"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.GetMap("db"), map[string]interface{}{"host": "a", "port": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("db after Replace and Unset = %v, want = %v", got, want)
+	}
+
+	if err := cfg.Delete("db"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Delete("servers.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.HasKey("db", "") {
+		t.Errorf("db exists after Delete and Reload")
+	}
+	if got, want := cfg.GetSliceString("servers"), []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("servers after Delete and Reload = %v, want = %v", got, want)
+	}
+	if err := cfg.Unset("db"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Unset("servers.0"); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetString("db.host"); got != "a" {
+		t.Errorf("db.host after Delete and Unset = %q, want = %q", got, "a")
+	}
+	if got, want := cfg.GetSliceString("servers"), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("servers after Delete and Unset = %v, want = %v", got, want)
+	}
+}
+
+func TestConfig_SnapshotSub(t *testing.T) {
+	cfg := config.New(config.Options{Interpolate: true})
+	err := cfg.LoadBytes([]byte(`
+app:
+  name: billing
+database:
+  host: localhost
+  port: 5432
+  user: ${app.name}
+  password: s3cr3t
+`), config.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.MarkSecret("database.password")
+
+	snap := cfg.Snapshot()
+	if err := cfg.Set("database.port", 6543); err != nil {
+		t.Fatal(err)
+	}
+	if got := snap.GetInt("database.port"); got != 5432 {
+		t.Errorf("snapshot database.port = %v, want = %v", got, 5432)
+	}
+	if err := snap.Set("database.port", 1); !errors.Is(err, config.ErrReadOnly) {
+		t.Errorf("snapshot Set() error = %v, want = %v", err, config.ErrReadOnly)
+	}
+	if err := snap.(*config.Config).SetSchema(config.NewSchema()); !errors.Is(err, config.ErrReadOnly) {
+		t.Errorf("snapshot SetSchema() error = %v, want = %v", err, config.ErrReadOnly)
+	}
+
+	snap.(*config.Config).MarkSecret("database.host")
+	if got := snap.(*config.Config).Redacted()["database"].(map[string]interface{})["host"]; got != "localhost" {
+		t.Errorf("snapshot MarkSecret() must not apply, host = %v", got)
+	}
+
+	// La vista se lee sin bloqueo mientras la original cambia
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if got := snap.GetInt("database.port"); got != 5432 {
+					t.Errorf("snapshot database.port = %v, want = %v", got, 5432)
+					return
+				}
+			}
+		}()
+	}
+	for j := 0; j < 100; j++ {
+		if err := cfg.Set("counter", j); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+
+	db := cfg.Sub("database")
+	if got := db.GetInt("port"); got != 6543 {
+		t.Errorf("Sub port = %v, want = %v", got, 6543)
+	}
+	if got := db.GetString("user"); got != "billing" {
+		t.Errorf("Sub user = %q, want = %q", got, "billing")
+	}
+	if s := fmt.Sprint(db); strings.Contains(s, "s3cr3t") {
+		t.Errorf("Sub String() = %s, want password redacted", s)
+	}
+	if got := cfg.Sub("missing").GetKeys(""); len(got) != 0 {
+		t.Errorf("Sub(missing) keys = %v, want none", got)
+	}
+
+	// Las escrituras sobre Sub se aplican a una copia propia
+	if err := db.Set("port", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.GetInt("port"); got != 1 {
+		t.Errorf("Sub port after Set = %v, want = %v", got, 1)
+	}
+	if got := cfg.GetInt("database.port"); got != 6543 {
+		t.Errorf("database.port after Sub Set = %v, want = %v", got, 6543)
+	}
+	if err := db.Unset("port"); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.GetInt("port"); got != 5432 {
+		t.Errorf("Sub port after Unset = %v, want = %v", got, 5432)
+	}
+	if err := db.Delete("host"); err != nil {
+		t.Fatal(err)
+	}
+	if db.HasKey("host", "") || cfg.GetString("database.host") != "localhost" {
+		t.Errorf("Sub Delete() must only remove the key from the view")
+	}
+}
+
+func TestConfig_TypedAccessors(t *testing.T) {
+	cfg := config.New(config.Options{})
+	err := cfg.LoadBytes([]byte(`{
+		"timeout": "1m30s",
+		"release": "2024-01-02T03:04:05Z",
+		"day": "2024-06-01",
+		"upload": "512MB",
+		"cache": "1.5GiB",
+		"endpoint": "https://api.example.com:8443/v1",
+		"retries": ["1s", "2s"],
+		"limits": {"body": "1KiB", "header": 2048}
+	}`), config.FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cfg.GetDuration("timeout"); got != 90*time.Second {
+		t.Errorf("GetDuration() = %v, want = %v", got, 90*time.Second)
+	}
+	if got, want := cfg.GetTime("release"), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("GetTime(release) = %v, want = %v", got, want)
+	}
+	if got := cfg.GetTime("day"); got.Month() != time.June || got.Day() != 1 {
+		t.Errorf("GetTime(day) = %v", got)
+	}
+	if got := cfg.GetBytesSize("upload"); got != 512_000_000 {
+		t.Errorf("GetBytesSize(upload) = %v, want = %v", got, 512_000_000)
+	}
+	if got := cfg.GetBytesSize("cache"); got != 3<<29 {
+		t.Errorf("GetBytesSize(cache) = %v, want = %v", got, 3<<29)
+	}
+	if u := cfg.GetURL("endpoint"); u == nil || u.Hostname() != "api.example.com" || u.Port() != "8443" {
+		t.Errorf("GetURL() = %v", u)
+	}
+	if got, want := cfg.GetSliceDuration("retries"), []time.Duration{time.Second, 2 * time.Second}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSliceDuration() = %v, want = %v", got, want)
+	}
+	if got, want := cfg.GetMapBytesSize("limits"), map[string]int64{"body": 1024, "header": 2048}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMapBytesSize() = %v, want = %v", got, want)
+	}
+
+	// Valores no convertibles: cero en los getters simples, error en las variantes E
+	if got := cfg.GetDuration("upload"); got != 0 {
+		t.Errorf("GetDuration(upload) = %v, want = 0", got)
+	}
+	if _, err := cfg.GetBytesSizeE("timeout"); !errors.Is(err, config.ErrTypeMismatch) {
+		t.Errorf("GetBytesSizeE(timeout) error = %v, want = %v", err, config.ErrTypeMismatch)
+	}
+	if _, err := cfg.GetURLE("missing"); !errors.Is(err, config.ErrKeyNotFound) {
+		t.Errorf("GetURLE(missing) error = %v, want = %v", err, config.ErrKeyNotFound)
+	}
+
+	custom := config.New(config.Options{TimeLayouts: []string{"02/01/2006"}})
+	if err := custom.Set("day", "15/03/2024"); err != nil {
+		t.Fatal(err)
+	}
+	if got := custom.GetTime("day"); got.Month() != time.March || got.Day() != 15 {
+		t.Errorf("GetTime(day) with custom layout = %v", got)
+	}
+}
+
+func TestConfig_IntegerWidths(t *testing.T) {
+	cfg := config.New(config.Options{})
+	err := cfg.LoadBytes([]byte(`{
+		"id": 9007199254740993,
+		"big": "18446744073709551615",
+		"negative": -1,
+		"wide": 3000000000,
+		"ratio": 1.5,
+		"ids": [1, 2, 3],
+		"quotas": {"a": 10, "b": -5}
+	}`), config.FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cfg.GetInt64("id"); got != 9007199254740993 {
+		t.Errorf("GetInt64(id) = %v, want = %v", got, int64(9007199254740993))
+	}
+	if got := cfg.GetUint64("big"); got != math.MaxUint64 {
+		t.Errorf("GetUint64(big) = %v, want = %v", got, uint64(math.MaxUint64))
+	}
+	if got := cfg.GetInt32("wide"); got != 0 {
+		t.Errorf("GetInt32(wide) = %v, want = 0", got)
+	}
+	if got, want := cfg.GetSliceUint("ids"), []uint{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSliceUint(ids) = %v, want = %v", got, want)
+	}
+
+	tests := []struct {
+		name string
+		get  func() error
+		want error
+	}{
+		{"int32 overflow", func() error { _, err := cfg.GetInt32E("wide"); return err }, config.ErrOverflow},
+		{"int64 overflow", func() error { _, err := cfg.GetInt64E("big"); return err }, config.ErrOverflow},
+		{"negative uint", func() error { _, err := cfg.GetUintE("negative"); return err }, config.ErrOverflow},
+		{"fractional", func() error { _, err := cfg.GetInt64E("ratio"); return err }, config.ErrTypeMismatch},
+		{"map element", func() error { _, err := cfg.GetMapUint64E("quotas"); return err }, config.ErrOverflow},
+	}
+	for _, tt := range tests {
+		if err := tt.get(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want = %v", tt.name, err, tt.want)
+		}
+	}
+
+	var keyErr *config.KeyError
+	if _, err := cfg.GetMapUint64E("quotas"); !errors.As(err, &keyErr) || keyErr.Key != "quotas.b" {
+		t.Errorf("GetMapUint64E() error = %v, want key quotas.b", err)
+	}
+}
+
+type testLevel int
+
+func TestConfig_GenericGet(t *testing.T) {
+	config.RegisterDecoder(func(v interface{}) (testLevel, error) {
+		switch v {
+		case "debug":
+			return 0, nil
+		case "info":
+			return 1, nil
+		}
+		return 0, fmt.Errorf("%w: unknown level %v", config.ErrTypeMismatch, v)
+	})
+
+	cfg := config.New(config.Options{})
+	err := cfg.LoadBytes([]byte(`
+server:
+  ports: [8080, 8081]
+  timeout: 5s
+  started: 2024-01-02T03:04:05Z
+  level: info
+  weights: {a: 1.5, b: 2}
+loggers: [debug, info]
+`), config.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ports, err := config.Get[[]uint16](cfg, "server.ports")
+	if err != nil || !reflect.DeepEqual(ports, []uint16{8080, 8081}) {
+		t.Errorf("Get[[]uint16]() = %v, %v", ports, err)
+	}
+	if got, err := config.Get[time.Duration](cfg, "server.timeout"); err != nil || got != 5*time.Second {
+		t.Errorf("Get[time.Duration]() = %v, %v", got, err)
+	}
+	if got, err := config.Get[time.Time](cfg, "server.started"); err != nil || got.Year() != 2024 {
+		t.Errorf("Get[time.Time]() = %v, %v", got, err)
+	}
+	if got, err := config.Get[map[string]float64](cfg, "server.weights"); err != nil || got["a"] != 1.5 {
+		t.Errorf("Get[map[string]float64]() = %v, %v", got, err)
+	}
+	if got, err := config.Get[testLevel](cfg, "server.level"); err != nil || got != 1 {
+		t.Errorf("Get[testLevel]() = %v, %v", got, err)
+	}
+	if got, err := config.Get[[]testLevel](cfg, "loggers"); err != nil || !reflect.DeepEqual(got, []testLevel{0, 1}) {
+		t.Errorf("Get[[]testLevel]() = %v, %v", got, err)
+	}
+
+	if _, err := config.Get[uint8](cfg, "server.ports.0"); !errors.Is(err, config.ErrOverflow) {
+		t.Errorf("Get[uint8]() error = %v, want = %v", err, config.ErrOverflow)
+	}
+	if _, err := config.Get[int](cfg, "missing"); !errors.Is(err, config.ErrKeyNotFound) {
+		t.Errorf("Get[int](missing) error = %v, want = %v", err, config.ErrKeyNotFound)
+	}
+	if got := config.GetOr(cfg, "server.level", 42); got != 42 {
+		t.Errorf("GetOr() = %v, want = %v", got, 42)
+	}
+	if got := config.GetOr(cfg.Sub("server"), "ports.1", 0); got != 8081 {
+		t.Errorf("GetOr(Sub) = %v, want = %v", got, 8081)
+	}
+}
+
+func TestConfig_StringPolicy(t *testing.T) {
+	data := []byte(`{"ratio": 0.1, "tiny": 1e-9, "tags": ["a", "b"], "db": {"port": 5432}}`)
+
+	tests := []struct {
+		policy config.StringPolicy
+		want   map[string]string
+	}{
+		{config.StringShortest, map[string]string{"ratio": "0.1", "tiny": "0.000000001", "tags": "", "db": ""}},
+		{config.StringJSON, map[string]string{"ratio": "0.1", "tiny": "0.000000001", "tags": `["a","b"]`, "db": `{"port":5432}`}},
+		{config.StringLegacy, map[string]string{"ratio": "0.100000", "tiny": "0.000000", "tags": "", "db": ""}},
+	}
+	for _, tt := range tests {
+		cfg := config.New(config.Options{StringPolicy: tt.policy})
+		if err := cfg.LoadBytes(data, config.FormatJSON); err != nil {
+			t.Fatal(err)
+		}
+		for key, want := range tt.want {
+			if got := cfg.GetString(key); got != want {
+				t.Errorf("policy %d: GetString(%s) = %q, want = %q", tt.policy, key, got, want)
+			}
+			// Get[string] y Unmarshal aplican la misma política
+			if got, err := config.Get[string](cfg, key); want != "" && (err != nil || got != want) {
+				t.Errorf("policy %d: Get[string](%s) = %q, %v, want = %q", tt.policy, key, got, err, want)
+			}
+		}
+	}
+
+	cfg := config.New(config.Options{StringPolicy: config.StringJSON})
+	if err := cfg.LoadBytes(data, config.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := cfg.GetStringE("tags"); err != nil || got != `["a","b"]` {
+		t.Errorf("GetStringE(tags) = %q, %v", got, err)
+	}
+
+	shortest := config.New(config.Options{})
+	if err := shortest.LoadBytes(data, config.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := shortest.GetStringE("tags"); !errors.Is(err, config.ErrTypeMismatch) {
+		t.Errorf("GetStringE(tags) error = %v, want = %v", err, config.ErrTypeMismatch)
+	}
+}
+
+func TestConfig_MergeStrategies(t *testing.T) {
+	base := []byte(`
+cors:
+  allowed_origins: [https://a.example.com]
+  max_age: 600
+middlewares:
+  - {name: auth, enabled: true}
+  - {name: gzip, level: 5}
+tags: [a, b]
+`)
+	overlay := []byte(`
+cors:
+  allowed_origins: [https://b.example.com, https://a.example.com]
+  max_age: $delete
+middlewares:
+  - {name: gzip, level: 9}
+  - {name: cache}
+tags: [b, c]
+`)
+
+	cfg := config.New(config.Options{})
+	if err := cfg.LoadBytes(base, config.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetMergeStrategy("cors.allowed_origins", config.MergeAppendUnique); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetMergeStrategy("middlewares", config.MergeByKey("name")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.LoadBytes(overlay, config.FormatYAML, config.WithMergeStrategy(config.MergeAppend)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := cfg.GetSliceString("cors.allowed_origins"), []string{"https://a.example.com", "https://b.example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("allowed_origins = %v, want = %v", got, want)
+	}
+	if cfg.HasKey("cors.max_age", "") {
+		t.Errorf("cors.max_age should be deleted by the overlay")
+	}
+	if origin, ok := cfg.Origin("cors.max_age"); ok {
+		t.Errorf("Origin(cors.max_age) = %v, want = not found", origin)
+	}
+	if got, want := cfg.GetSliceString("tags"), []string{"a", "b", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags = %v, want = %v", got, want)
+	}
+	want := []interface{}{
+		map[string]interface{}{"name": "auth", "enabled": true},
+		map[string]interface{}{"name": "gzip", "level": 9},
+		map[string]interface{}{"name": "cache"},
+	}
+	if got := cfg.GetSlice("middlewares"); !reflect.DeepEqual(got, want) {
+		t.Errorf("middlewares = %v, want = %v", got, want)
+	}
+
+	// Set reemplaza el slice efectivo aunque la clave tenga una estrategia
+	if err := cfg.Set("cors.allowed_origins", []interface{}{"*"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetSliceString("cors.allowed_origins"); !reflect.DeepEqual(got, []string{"*"}) {
+		t.Errorf("allowed_origins after Set = %v, want = [*]", got)
+	}
+
+	if err := cfg.SetMergeStrategy("tags", "zip"); !errors.Is(err, config.ErrMergeStrategy) {
+		t.Errorf("SetMergeStrategy(zip) error = %v, want = %v", err, config.ErrMergeStrategy)
+	}
+
+	// Delete resuelve el índice contra el slice combinado
+	cfg = config.New(config.Options{})
+	if err := cfg.LoadBytes([]byte("l: [a, b]"), config.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.LoadBytes([]byte("l: [c, d]"), config.FormatYAML, config.WithMergeStrategy(config.MergeAppend)); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("l.2", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Delete("l.1"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.GetSliceString("l"), []string{"a", "x", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("l after Delete(l.1) = %v, want = %v", got, want)
+	}
+	if err := cfg.Delete("l.2"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.GetSliceString("l"), []string{"a", "x"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("l after Delete(l.2) = %v, want = %v", got, want)
+	}
+
+	// Un elemento repetido en varias fuentes se elimina del slice combinado sin reaparecer
+	cfg = config.New(config.Options{})
+	if err := cfg.LoadBytes([]byte("origins: [a, b]"), config.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.LoadBytes([]byte("origins: [b, c]"), config.FormatYAML, config.WithMergeStrategy(config.MergeAppendUnique)); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Delete("origins.1"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.GetSliceString("origins"), []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("origins after Delete(origins.1) = %v, want = %v", got, want)
+	}
+}
+
+func TestConfig_LoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "app.yaml")
+	err := os.WriteFile(base, []byte(`
+server:
+  host: localhost
+  port: 8080
+log:
+  level: debug
+profiles:
+  production:
+    log:
+      level: warn
+---
+$profile: production, staging
+server:
+  host: 0.0.0.0
+---
+$profile: [test]
+server:
+  port: 0
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app.production.yaml"), []byte("server:\n  port: 80\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.New(config.Options{})
+	if err := cfg.LoadProfile(base, "production"); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"server": map[string]interface{}{"host": "0.0.0.0", "port": 80},
+		"log":    map[string]interface{}{"level": "warn"},
+	}
+	if got := cfg.Redacted(); !reflect.DeepEqual(got, want) {
+		t.Errorf("production = %v, want = %v", got, want)
+	}
+	if origin, _ := cfg.Origin("server.port"); origin.Source != filepath.Join(dir, "app.production.yaml") {
+		t.Errorf("Origin(server.port) = %v", origin)
+	}
+
+	// El perfil activo se toma de la variable de entorno
+	t.Setenv("APP_PROFILE", "test")
+	cfg = config.New(config.Options{})
+	if err := cfg.LoadProfile(base, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetString("server.host"); got != "localhost" {
+		t.Errorf("test server.host = %q, want = %q", got, "localhost")
+	}
+	if got := cfg.GetInt("server.port"); got != 0 {
+		t.Errorf("test server.port = %v, want = 0", got)
+	}
+	if cfg.HasKey("profiles", "") {
+		t.Errorf("the profiles section should not be part of the config")
+	}
+
+	// Varios perfiles: las secciones se aplican en orden y los archivos de perfil al final
+	multi := filepath.Join(dir, "multi.yaml")
+	err = os.WriteFile(multi, []byte("port: 1\nprofiles:\n  a: {name: A, port: 2}\n  b: {name: B, port: 3}\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "multi.a.yaml"), []byte("port: 4\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		profiles string
+		name     string
+		port     int
+	}{
+		{profiles: "a,b", name: "B", port: 4},
+		{profiles: "b,a", name: "A", port: 4},
+		{profiles: "b", name: "B", port: 3},
+	} {
+		cfg := config.New(config.Options{})
+		if err := cfg.LoadProfile(multi, tt.profiles); err != nil {
+			t.Fatal(err)
+		}
+		if got := cfg.GetString("name"); got != tt.name {
+			t.Errorf("LoadProfile(%q) name = %q, want = %q", tt.profiles, got, tt.name)
+		}
+		if got := cfg.GetInt("port"); got != tt.port {
+			t.Errorf("LoadProfile(%q) port = %v, want = %v", tt.profiles, got, tt.port)
+		}
+	}
+
+	if err := cfg.LoadProfile(filepath.Join(dir, "missing.yaml"), "production"); !errors.Is(err, config.ErrReadFile) {
+		t.Errorf("LoadProfile(missing) error = %v, want = %v", err, config.ErrReadFile)
+	}
+}
+
+func TestConfig_Includes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.yaml": `
+include:
+  - base.yaml
+  - services/*.yaml
+server:
+  port: 9090
+database:
+  $ref: db/database.yaml
+  pool: 20
+`,
+		"base.yaml":          "server:\n  host: localhost\n  port: 8080\n",
+		"services/auth.yaml": "services:\n  auth: {url: http://auth}\n",
+		"services/mail.yaml": "services:\n  mail: {url: http://mail}\n",
+		"db/database.yaml":   "host: db.local\npool: 5\n",
+		"loop/a.yaml":        "include: b.yaml\n",
+		"loop/b.yaml":        "include: [a.yaml]\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.New(config.Options{})
+	if err := cfg.LoadFile(filepath.Join(dir, "app.yaml"), config.WithIncludes()); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"server":   map[string]interface{}{"host": "localhost", "port": 9090},
+		"services": map[string]interface{}{"auth": map[string]interface{}{"url": "http://auth"}, "mail": map[string]interface{}{"url": "http://mail"}},
+		"database": map[string]interface{}{"host": "db.local", "pool": 20},
+	}
+	if got := cfg.Redacted(); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadFile() = %v, want = %v", got, want)
+	}
+
+	err := config.New(config.Options{}).LoadFile(filepath.Join(dir, "loop", "a.yaml"), config.WithIncludes())
+	if !errors.Is(err, config.ErrIncludeCycle) {
+		t.Fatalf("LoadFile(loop) error = %v, want = %v", err, config.ErrIncludeCycle)
+	}
+	if !strings.Contains(err.Error(), "a.yaml -> ") || !strings.Contains(err.Error(), "b.yaml -> ") {
+		t.Errorf("LoadFile(loop) error = %v, want the include chain", err)
+	}
+
+	// Sin WithIncludes las directivas son claves comunes; las referencias "#" no se expanden
+	plain := filepath.Join(dir, "plain.yaml")
+	if err := os.WriteFile(plain, []byte("include: [x.yaml]\nschema:\n  $ref: '#/definitions/x'\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg = config.New(config.Options{})
+	if err := cfg.LoadFile(plain); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetSliceString("include"); !reflect.DeepEqual(got, []string{"x.yaml"}) {
+		t.Errorf("include = %v, want = [x.yaml]", got)
+	}
+	if err := os.WriteFile(plain, []byte("schema:\n  $ref: '#/definitions/x'\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.LoadFile(plain, config.WithIncludes()); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetString(`schema."$ref"`); got != "#/definitions/x" {
+		t.Errorf("schema.$ref = %q, want = %q", got, "#/definitions/x")
+	}
+
+	// Watch también recarga al cambiar un archivo referenciado
+	cfg = config.New(config.Options{})
+	if err := cfg.LoadFile(filepath.Join(dir, "app.yaml"), config.WithIncludes()); err != nil {
+		t.Fatal(err)
+	}
+	changed := make(chan interface{}, 1)
+	cfg.OnChange("database.host", func(_, new interface{}) { changed <- new })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cfg.Watch(ctx, config.WatchOptions{Interval: 10 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "db", "database.yaml"), []byte("host: db.prod.local\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-changed:
+		if got != "db.prod.local" {
+			t.Errorf("database.host = %v, want = %v", got, "db.prod.local")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch() did not report the change of the referenced file")
+	}
+}
+
+func TestConfig_LoadReaderFS(t *testing.T) {
+	cfg := config.New(config.Options{})
+	if err := cfg.LoadBytes([]byte("legacy: true\n"), config.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	stream := strings.NewReader(`
+server:
+  port: 8080
+  tags: [a]
+---
+server:
+  port: 9090
+legacy: $delete
+---
+server:
+  tags: [b]
+`)
+	if err := cfg.LoadReader(stream, config.FormatYAML, config.WithMergeStrategy(config.MergeAppend)); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetInt("server.port"); got != 9090 {
+		t.Errorf("server.port = %v, want = %v", got, 9090)
+	}
+	if got, want := cfg.GetSliceString("server.tags"), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("server.tags = %v, want = %v", got, want)
+	}
+	if cfg.HasKey("legacy", "") {
+		t.Errorf("legacy should be deleted by the second document")
+	}
+
+	fsys := fstest.MapFS{
+		"defaults/01-server.yaml":  {Data: []byte("include: shared/log.yaml\nserver:\n  host: localhost\n")},
+		"defaults/02-db.json":      {Data: []byte(`{"database": {"port": 5432}}`)},
+		"defaults/shared/log.yaml": {Data: []byte("log:\n  level: info\n")},
+	}
+	cfg = config.New(config.Options{})
+	if err := cfg.LoadFS(fsys, "defaults/*.*", config.WithIncludes()); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetString("log.level"); got != "info" {
+		t.Errorf("log.level = %q, want = %q", got, "info")
+	}
+	if got := cfg.GetInt("database.port"); got != 5432 {
+		t.Errorf("database.port = %v, want = %v", got, 5432)
+	}
+	if origin, _ := cfg.Origin("server.host"); origin.Source != "defaults/01-server.yaml" {
+		t.Errorf("Origin(server.host) = %v", origin)
+	}
+	if err := cfg.LoadFS(fsys, "missing/*.yaml"); !errors.Is(err, config.ErrReadFile) {
+		t.Errorf("LoadFS(missing) error = %v, want = %v", err, config.ErrReadFile)
+	}
+}
+
+func TestConfig_Providers(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		version    = 1
+		failing    bool
+		broken     = true
+		notChanged int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failing {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		etag := fmt.Sprintf(`"v%d"`, version)
+		if r.Header.Get("If-None-Match") == etag {
+			notChanged++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		if broken {
+			fmt.Fprint(w, `{"feature": `)
+			return
+		}
+		fmt.Fprintf(w, `{"feature": {"version": %d}}`, version)
+	}))
+	defer srv.Close()
+
+	cfg := config.New(config.Options{})
+	if err := cfg.LoadBytes([]byte("feature:\n  version: 0\n  name: beta\n"), config.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	provider := config.HTTPProvider(srv.URL, config.HTTPOptions{})
+	if err := cfg.LoadProvider(context.Background(), "flags-service", provider); err == nil {
+		t.Fatal("LoadProvider() with an invalid payload error = nil")
+	}
+
+	// Un contenido rechazado no guarda su ETag: la siguiente carga lo pide completo
+	mu.Lock()
+	broken = false
+	mu.Unlock()
+	if err := cfg.LoadProvider(context.Background(), "flags-service", provider); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetInt("feature.version"); got != 1 {
+		t.Errorf("feature.version = %v, want = 1", got)
+	}
+	if origin, _ := cfg.Origin("feature.version"); origin.String() != "remote:flags-service" {
+		t.Errorf("Origin() = %v, want = remote:flags-service", origin)
+	}
+	shared := config.New(config.Options{})
+	if err := shared.LoadProvider(context.Background(), "flags-service", provider); err != nil {
+		t.Fatal(err)
+	}
+	if got := shared.GetInt("feature.version"); got != 1 {
+		t.Errorf("feature.version with a shared provider = %v, want = 1", got)
+	}
+
+	updated := make(chan interface{}, 1)
+	cfg.OnChange("feature.version", func(_, new interface{}) { updated <- new })
+	errs := make(chan error, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg.WatchProvider(ctx, "flags-service", provider, config.ProviderOptions{
+		Interval:   10 * time.Millisecond,
+		MaxBackoff: 40 * time.Millisecond,
+		OnError:    func(_ string, err error) { errs <- err },
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	version, failing = 2, true
+	mu.Unlock()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, config.ErrProvider) {
+			t.Errorf("OnError() = %v, want = %v", err, config.ErrProvider)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnError was not called")
+	}
+	// Se conservan los últimos valores válidos
+	if got := cfg.GetInt("feature.version"); got != 1 {
+		t.Errorf("feature.version after error = %v, want = 1", got)
+	}
+
+	mu.Lock()
+	failing = false
+	mu.Unlock()
+	select {
+	case got := <-updated:
+		if got != 2 {
+			t.Errorf("OnChange() = %v, want = 2", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnChange was not called")
+	}
+
+	mu.Lock()
+	if notChanged == 0 {
+		t.Errorf("no request was answered with 304 Not Modified")
+	}
+	mu.Unlock()
+	if got := cfg.GetString("feature.name"); got != "beta" {
+		t.Errorf("feature.name = %q, want = %q", got, "beta")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("log:\n  level: info\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "database.port"), []byte("5432\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg = config.New(config.Options{})
+	if err := cfg.LoadProvider(context.Background(), "configmap", config.DirectoryProvider(dir)); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetString("log.level"); got != "info" {
+		t.Errorf("log.level = %q, want = %q", got, "info")
+	}
+	if got := cfg.GetInt("database.port"); got != 5432 {
+		t.Errorf("database.port = %v, want = %v", got, 5432)
+	}
+
+	data, format, err := config.DirectoryProvider(dir).Fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != config.FormatJSON || !strings.Contains(string(data), `"port":"5432"`) {
+		t.Errorf("Fetch() = %s (%s), want the combined JSON", data, format)
+	}
+}